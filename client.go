@@ -32,7 +32,7 @@ type FetchTicketListingsInput struct {
 	// Required fields
 	Country Country
 
-	// Regions for which to fetch ticket listings from.
+	// Regions for which to fetch ticket listings from. These must be regions of Country. See Country.Regions.
 	// Leave this unset or empty to fetch listings from any region.
 	// Defaults to any region (unset).
 	Regions []Region
@@ -73,7 +73,11 @@ func (f FetchTicketListingsInput) Validate() error {
 		return errors.New("country must be set")
 	}
 	if !Countries.Contains(f.Country) {
-		return fmt.Errorf("country '%s' is not valid", f.Country)
+		return fmt.Errorf("country '%s' is not valid", f.Country.Value)
+	}
+	err := validateRegions(f.Country, f.Regions)
+	if err != nil {
+		return err
 	}
 	if f.CreatedBefore.Before(f.CreatedAfter) {
 		return errors.New("created after time must be after the created before time")
@@ -0,0 +1,223 @@
+package twigots
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrNoExchangeRate is returned when a price cannot be converted as there is no exchange rate for a currency.
+var ErrNoExchangeRate = errors.New("no exchange rate")
+
+// Converter converts prices between currencies.
+type Converter interface {
+	// Convert converts a price to the currency specified.
+	// If the price is already in the currency, it is returned unchanged.
+	Convert(price Price, currency Currency) (Price, error)
+}
+
+// StaticConverter is a converter that uses a fixed table of exchange rates.
+type StaticConverter struct {
+	base  Currency
+	rates map[Currency]*big.Rat
+}
+
+// NewStaticConverter creates a converter using a table of exchange rates relative to a base currency.
+// Each rate is the number of major units of a currency equal to one major unit of the base currency
+// e.g. with a base of GBP, a EUR rate of 1.17 means £1 = €1.17.
+//
+// The base currency does not need to be included in the rates. Rates must be positive.
+func NewStaticConverter(base Currency, rates map[Currency]float64) (*StaticConverter, error) {
+	ratRates := make(map[Currency]*big.Rat, len(rates))
+	for currency, rate := range rates {
+		// Use the shortest decimal representation of the rate, so e.g. 1.17 is exactly 117/100
+		ratRate, ok := new(big.Rat).SetString(strconv.FormatFloat(rate, 'f', -1, 64))
+		if !ok {
+			return nil, fmt.Errorf("rate for currency '%s' is not valid", currency.Value)
+		}
+		ratRates[currency] = ratRate
+	}
+
+	return newStaticConverter(base, ratRates)
+}
+
+func newStaticConverter(base Currency, rates map[Currency]*big.Rat) (*StaticConverter, error) {
+	if Currencies.Parse(base.Value) == nil {
+		return nil, fmt.Errorf("base currency '%s' is not valid", base.Value)
+	}
+
+	converterRates := make(map[Currency]*big.Rat, len(rates)+1)
+	for currency, rate := range rates {
+		if rate.Sign() <= 0 {
+			return nil, fmt.Errorf("rate for currency '%s' must be positive", currency.Value)
+		}
+		converterRates[currency] = rate
+	}
+	converterRates[base] = big.NewRat(1, 1)
+
+	return &StaticConverter{
+		base:  base,
+		rates: converterRates,
+	}, nil
+}
+
+// ParseRates parses a table of exchange rates from JSON, returning a static converter using the rates.
+//
+// JSON should be an object with the base currency, and the rates relative to the base currency
+// e.g. {"base": "GBP", "rates": {"EUR": 1.17, "USD": 1.27}}. See NewStaticConverter for the meaning of rates.
+func ParseRates(r io.Reader) (*StaticConverter, error) {
+	var ratesFile struct {
+		Base  Currency                 `json:"base"`
+		Rates map[Currency]json.Number `json:"rates"`
+	}
+
+	decoder := json.NewDecoder(r)
+	decoder.UseNumber()
+	err := decoder.Decode(&ratesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode rates: %w", err)
+	}
+
+	rates := make(map[Currency]*big.Rat, len(ratesFile.Rates))
+	for currency, rate := range ratesFile.Rates {
+		// Parse rates exactly, rather than going through floats
+		ratRate, ok := new(big.Rat).SetString(rate.String())
+		if !ok {
+			return nil, fmt.Errorf("rate for currency '%s' is not valid", currency.Value)
+		}
+		rates[currency] = ratRate
+	}
+
+	return newStaticConverter(ratesFile.Base, rates)
+}
+
+// Base returns the base currency of the converter rates.
+func (c *StaticConverter) Base() Currency {
+	return c.base
+}
+
+// Convert converts a price to the currency specified.
+// The converted amount is rounded to the nearest minor unit, with halves rounded away from zero.
+//
+// If there is no rate for either currency, ErrNoExchangeRate is returned.
+func (c *StaticConverter) Convert(price Price, currency Currency) (Price, error) {
+	if price.Currency == currency {
+		return price, nil
+	}
+
+	fromRate, ok := c.rates[price.Currency]
+	if !ok {
+		return Price{}, fmt.Errorf("%w for currency '%s'", ErrNoExchangeRate, price.Currency.Value)
+	}
+	toRate, ok := c.rates[currency]
+	if !ok {
+		return Price{}, fmt.Errorf("%w for currency '%s'", ErrNoExchangeRate, currency.Value)
+	}
+
+	// amount in target minor units =
+	// amount in source minor units / source minor units * (target rate / source rate) * target minor units
+	amount := new(big.Rat).SetInt64(int64(price.Amount))
+	amount.Mul(amount, toRate)
+	amount.Quo(amount, fromRate)
+	amount.Mul(amount, new(big.Rat).SetInt64(int64(currency.minorUnits())))
+	amount.Quo(amount, new(big.Rat).SetInt64(int64(price.Currency.minorUnits())))
+
+	return Price{
+		Currency: currency,
+		Amount:   roundRat(amount),
+	}, nil
+}
+
+// FileConverter is a converter that uses a table of exchange rates read from a JSON file.
+// The file is reloaded when it changes, so rates can be updated without restarting.
+// See ParseRates for the file format.
+//
+// A FileConverter is safe for concurrent use.
+type FileConverter struct {
+	path string
+
+	mutex     sync.Mutex
+	converter *StaticConverter
+	modTime   time.Time
+	size      int64
+}
+
+// NewFileConverter creates a converter using a JSON file of exchange rates.
+// The file is read immediately, returning an error if it cannot be read.
+func NewFileConverter(path string) (*FileConverter, error) {
+	converter := &FileConverter{path: path}
+
+	_, err := converter.load()
+	if err != nil {
+		return nil, err
+	}
+
+	return converter, nil
+}
+
+// Convert converts a price to the currency specified, using the rates in the file. See StaticConverter.Convert.
+//
+// If the file has changed since it was last read, it is read again before converting.
+// If the file cannot be read, an error is returned.
+func (c *FileConverter) Convert(price Price, currency Currency) (Price, error) {
+	converter, err := c.load()
+	if err != nil {
+		return Price{}, err
+	}
+	return converter.Convert(price, currency)
+}
+
+// load gets the converter using the rates in the file, reading the file if it has changed since it was last read.
+func (c *FileConverter) load() (*StaticConverter, error) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	fileInfo, err := os.Stat(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat rates file: %w", err)
+	}
+
+	if c.converter != nil && fileInfo.ModTime().Equal(c.modTime) && fileInfo.Size() == c.size {
+		return c.converter, nil
+	}
+
+	file, err := os.Open(c.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open rates file: %w", err)
+	}
+	defer file.Close()
+
+	converter, err := ParseRates(file)
+	if err != nil {
+		return nil, err
+	}
+
+	c.converter = converter
+	c.modTime = fileInfo.ModTime()
+	c.size = fileInfo.Size()
+
+	return converter, nil
+}
+
+// roundRat rounds a rational number to the nearest integer, with halves rounded away from zero.
+func roundRat(rat *big.Rat) int {
+	numerator := rat.Num()
+	denominator := rat.Denom()
+
+	// Calculate (2 * |numerator| + denominator) / (2 * denominator), then restore the sign
+	absNumerator := new(big.Int).Abs(numerator)
+	doubled := new(big.Int).Mul(absNumerator, big.NewInt(2))
+	doubled.Add(doubled, denominator)
+	rounded := doubled.Quo(doubled, new(big.Int).Mul(denominator, big.NewInt(2)))
+	if numerator.Sign() < 0 {
+		rounded.Neg(rounded)
+	}
+
+	return int(rounded.Int64())
+}
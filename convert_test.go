@@ -0,0 +1,123 @@
+package twigots
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticConverterConvert(t *testing.T) {
+	converter, err := NewStaticConverter(CurrencyGBP, map[Currency]float64{
+		CurrencyEUR: 1.17,
+		CurrencyJPY: 190,
+	})
+	require.NoError(t, err)
+	require.Equal(t, CurrencyGBP, converter.Base())
+
+	// Base to other currency
+	price, err := converter.Convert(Price{Currency: CurrencyGBP, Amount: 80 * 100}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyEUR, Amount: 9360}, price)
+
+	// Other currency to base, rounded to the nearest penny
+	price, err = converter.Convert(Price{Currency: CurrencyEUR, Amount: 80 * 100}, CurrencyGBP)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 6838}, price) // £68.376...
+
+	// Between non-base currencies, with different exponents
+	price, err = converter.Convert(Price{Currency: CurrencyEUR, Amount: 117 * 100}, CurrencyJPY)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyJPY, Amount: 19000}, price)
+
+	price, err = converter.Convert(Price{Currency: CurrencyJPY, Amount: 19000}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyEUR, Amount: 117 * 100}, price)
+
+	// Same currency is unchanged
+	price, err = converter.Convert(Price{Currency: CurrencyEUR, Amount: 1}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyEUR, Amount: 1}, price)
+
+	// Missing rates
+	_, err = converter.Convert(Price{Currency: CurrencyUSD, Amount: 100}, CurrencyGBP)
+	require.ErrorIs(t, err, ErrNoExchangeRate)
+	_, err = converter.Convert(Price{Currency: CurrencyGBP, Amount: 100}, CurrencyUSD)
+	require.ErrorIs(t, err, ErrNoExchangeRate)
+}
+
+func TestNewStaticConverterInvalid(t *testing.T) {
+	_, err := NewStaticConverter(Currency{Value: "XXX"}, nil)
+	require.Error(t, err)
+
+	_, err = NewStaticConverter(CurrencyGBP, map[Currency]float64{CurrencyEUR: 0})
+	require.Error(t, err)
+}
+
+func TestRoundRat(t *testing.T) {
+	converter, err := NewStaticConverter(CurrencyGBP, map[Currency]float64{CurrencyEUR: 0.5})
+	require.NoError(t, err)
+
+	// Halves are rounded away from zero
+	price, err := converter.Convert(Price{Currency: CurrencyGBP, Amount: 3}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, 2, price.Amount)
+
+	price, err = converter.Convert(Price{Currency: CurrencyGBP, Amount: -3}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, -2, price.Amount)
+}
+
+func TestParseRates(t *testing.T) {
+	converter, err := ParseRates(strings.NewReader(`{"base": "EUR", "rates": {"GBP": 0.855, "USD": 1.08}}`))
+	require.NoError(t, err)
+	require.Equal(t, CurrencyEUR, converter.Base())
+
+	price, err := converter.Convert(Price{Currency: CurrencyEUR, Amount: 100 * 100}, CurrencyGBP)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 8550}, price)
+
+	_, err = ParseRates(strings.NewReader(`{"base": "EUR", "rates": {"XXX": 1}}`))
+	require.Error(t, err)
+
+	_, err = ParseRates(strings.NewReader(`{"base": "EUR", "rates": {"GBP": -1}}`))
+	require.Error(t, err)
+}
+
+func TestFileConverter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rates.json")
+	writeRates := func(rates string, modTime time.Time) {
+		err := os.WriteFile(path, []byte(rates), 0o600)
+		require.NoError(t, err)
+		err = os.Chtimes(path, modTime, modTime)
+		require.NoError(t, err)
+	}
+
+	modTime := time.Now().Add(-time.Hour)
+	writeRates(`{"base": "GBP", "rates": {"EUR": 1.2}}`, modTime)
+
+	converter, err := NewFileConverter(path)
+	require.NoError(t, err)
+
+	price, err := converter.Convert(Price{Currency: CurrencyGBP, Amount: 100}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyEUR, Amount: 120}, price)
+
+	// Updated rates should be used
+	writeRates(`{"base": "GBP", "rates": {"EUR": 1.1}}`, modTime.Add(time.Minute))
+	price, err = converter.Convert(Price{Currency: CurrencyGBP, Amount: 100}, CurrencyEUR)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyEUR, Amount: 110}, price)
+
+	// Invalid rates should error
+	writeRates(`{"base": "GBP", "rates": {"EUR": "abc"}}`, modTime.Add(2*time.Minute))
+	_, err = converter.Convert(Price{Currency: CurrencyGBP, Amount: 100}, CurrencyEUR)
+	require.Error(t, err)
+
+	// Missing files should error
+	_, err = NewFileConverter(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
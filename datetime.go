@@ -31,6 +31,7 @@ func (dt *DateTime) UnmarshalJSON(data []byte) error {
 }
 
 // Date is a date (with no time).
+// Dates are parsed in UTC. See Event.StartsAt and Tour.FirstEventDay for dates in the local time zone of an event.
 type Date struct{ time.Time }
 
 func (d *Date) UnmarshalJSON(data []byte) error {
@@ -48,7 +49,8 @@ func (d *Date) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
-// Date is a time (with no date).
+// Time is a time (with no date).
+// Times are parsed in UTC on the zero date. See Event.StartsAt for the time in the local time zone of an event.
 type Time struct{ time.Time }
 
 func (t *Time) UnmarshalJSON(data []byte) error {
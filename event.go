@@ -1,5 +1,14 @@
 package twigots
 
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ahobsonsayers/twigots/geo"
+)
+
 // Event contains the details of an event.
 type Event struct {
 	Id       string `json:"id"`
@@ -15,6 +24,28 @@ type Event struct {
 	Lineup []Lineup `json:"participants"`
 }
 
+// StartsAt gets the time the event starts, by combining the event date and time in the time zone of the
+// event venue. See Location.TimeZone.
+//
+// If the event has no date or time, or the time zone of the venue is unknown, an error is returned.
+func (e Event) StartsAt() (time.Time, error) {
+	if e.Date.IsZero() {
+		return time.Time{}, errors.New("event has no date")
+	}
+	if e.Time.IsZero() {
+		return time.Time{}, errors.New("event has no start time")
+	}
+
+	timeZone, err := e.Venue.Location.TimeZone()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get event time zone: %w", err)
+	}
+
+	year, month, day := e.Date.Date()
+	hour, minute, second := e.Time.Clock()
+	return time.Date(year, month, day, hour, minute, second, 0, timeZone), nil
+}
+
 // Lineup contains the details of the event lineup.
 type Lineup struct {
 	Artist  Artist `json:"participant"`
@@ -36,6 +67,17 @@ type Venue struct {
 	Postcode string   `json:"postcode"`
 }
 
+// Coordinates gets the coordinates of the venue from its postcode.
+// Only UK postcodes are supported. See geo.PostcodeCoordinates for how postcodes are resolved.
+//
+// If the venue has no postcode, or its postcode is unknown, an error wrapping geo.ErrUnknownPostcode is returned.
+func (v Venue) Coordinates() (geo.Coordinates, error) {
+	if v.Postcode == "" {
+		return geo.Coordinates{}, fmt.Errorf("%w: venue '%s' has no postcode", geo.ErrUnknownPostcode, v.Name)
+	}
+	return geo.PostcodeCoordinates(v.Postcode)
+}
+
 // Venue contains the details of an event location.
 type Location struct {
 	Id       string  `json:"id"`
@@ -43,14 +85,121 @@ type Location struct {
 	FullName string  `json:"name"`
 	Country  Country `json:"countryCode"`
 	Region   Region  `json:"regionCode"`
+
+	// TimeZoneName is the IANA time zone of the location e.g. Europe/London. Can be empty.
+	// Use TimeZone to get the time zone of the location.
+	TimeZoneName string `json:"dateTimeZone"`
+}
+
+// TimeZone gets the time zone of the location.
+// The time zone of the location is used if set and valid, otherwise the time zone of the country is used.
+// See Country.TimeZone.
+func (l Location) TimeZone() (*time.Location, error) {
+	if l.TimeZoneName != "" {
+		timeZone, err := time.LoadLocation(l.TimeZoneName)
+		if err == nil {
+			return timeZone, nil
+		}
+	}
+	return l.Country.TimeZone()
 }
 
 // Event contains the details of a tour.
 type Tour struct {
-	Id         string   `json:"tourId"`
-	Name       string   `json:"tourName"`
-	Slug       string   `json:"slug"`
-	FirstEvent *Date    `json:"minDate"`      // 2024-06-06
-	LastEvent  *Date    `json:"maxDate"`      // 2024-11-14
-	Countries  []string `json:"countryCodes"` // TODO use enum - requires all countries to be added
+	Id         string `json:"tourId"`
+	Name       string `json:"tourName"`
+	Slug       string `json:"slug"`
+	FirstEvent *Date  `json:"minDate"` // 2024-06-06
+	LastEvent  *Date  `json:"maxDate"` // 2024-11-14
+
+	// Countries are the countries the tour visits.
+	// Tours can visit countries that are not supported (see Countries). These are kept, so will have a
+	// value (the country code) but will not be in Countries.
+	Countries []Country `json:"countryCodes"`
+}
+
+// TimeZone gets the time zone of the tour. This is the time zone shared by all countries the tour visits.
+// See Country.TimeZone.
+//
+// If the tour visits no countries, countries without a known time zone, or countries in different time zones,
+// an error is returned.
+func (t Tour) TimeZone() (*time.Location, error) {
+	if len(t.Countries) == 0 {
+		return nil, errors.New("tour has no countries")
+	}
+
+	var tourTimeZone *time.Location
+	for _, country := range t.Countries {
+		timeZone, err := country.TimeZone()
+		if err != nil {
+			return nil, err
+		}
+
+		if tourTimeZone != nil && timeZone.String() != tourTimeZone.String() {
+			return nil, fmt.Errorf("tour countries are in different time zones: %s and %s", tourTimeZone, timeZone)
+		}
+		tourTimeZone = timeZone
+	}
+
+	return tourTimeZone, nil
+}
+
+// FirstEventDay gets the start (midnight) of the day of the first event of the tour, in the time zone of the tour.
+// See Tour.TimeZone.
+//
+// If the tour has no first event date, or the time zone of the tour is unknown, an error is returned.
+func (t Tour) FirstEventDay() (time.Time, error) {
+	return t.eventDay(t.FirstEvent, "first")
+}
+
+// LastEventDay gets the start (midnight) of the day of the last event of the tour, in the time zone of the tour.
+// See Tour.TimeZone.
+//
+// If the tour has no last event date, or the time zone of the tour is unknown, an error is returned.
+func (t Tour) LastEventDay() (time.Time, error) {
+	return t.eventDay(t.LastEvent, "last")
+}
+
+func (t Tour) eventDay(date *Date, description string) (time.Time, error) {
+	if date == nil || date.IsZero() {
+		return time.Time{}, fmt.Errorf("tour has no %s event date", description)
+	}
+
+	timeZone, err := t.TimeZone()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to get tour time zone: %w", err)
+	}
+
+	year, month, day := date.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, timeZone), nil
+}
+
+func (t *Tour) UnmarshalJSON(data []byte) error {
+	// Use an alias type to prevent recursion, with country codes as strings as not all countries are supported
+	type tourAlias Tour
+	tour := struct {
+		*tourAlias
+		Countries []string `json:"countryCodes"`
+	}{
+		tourAlias: (*tourAlias)(t),
+	}
+
+	err := json.Unmarshal(data, &tour)
+	if err != nil {
+		return err
+	}
+
+	t.Countries = nil
+	if tour.Countries != nil {
+		t.Countries = make([]Country, 0, len(tour.Countries))
+		for _, countryCode := range tour.Countries {
+			country := Countries.Parse(countryCode)
+			if country == nil {
+				country = &Country{countryCode}
+			}
+			t.Countries = append(t.Countries, *country)
+		}
+	}
+
+	return nil
 }
@@ -8,6 +8,7 @@ import (
 
 	"github.com/ahobsonsayers/twigots"
 	"github.com/ahobsonsayers/twigots/filter"
+	"github.com/ahobsonsayers/twigots/template"
 )
 
 func main() {
@@ -24,7 +25,7 @@ func main() {
 		context.Background(),
 		twigots.FetchTicketListingsInput{
 			// Required
-			Country: twigots.CountryUnitedKingdom, // Only UK is supported at the moment
+			Country: twigots.CountryUnitedKingdom, // See twigots.Countries for all supported countries
 			// Optional. See all options in godoc
 			CreatedBefore: time.Now(),
 			CreatedAfter:  time.Now().Add(time.Duration(-5 * time.Minute)), // 5 mins ago
@@ -37,6 +38,17 @@ func main() {
 
 	log.Printf("Fetched %d ticket listings", len(listings))
 
+	// Create a template to describe ticket listings
+	// Templates are executed with a view of the listing, and can use helper functions such as price and discount
+	listingTemplate, err := template.Parse(
+		"listing",
+		`{{.Event.Name}} on {{eventStart .Event}}: {{.NumTickets}} ticket(s) at {{price .TicketPrice}} each `+
+			`(original price {{price .OriginalTicketPrice}}, {{discount .Discount}} off) {{listingURL .}}`,
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
 	// Filter ticket listings just by name
 	// Use the default event name similarity (0.9) to allow minor mismatches
 	hamiltonListings := filter.FilterTicketListings(
@@ -44,16 +56,11 @@ func main() {
 		filter.EventName("Hamilton", filter.DefaultEventNameSimilarity),
 	)
 	for idx := 0; idx < len(hamiltonListings); idx++ {
-		listing := listings[idx]
-
-		slog.Info(
-			"Found Hamilton ticket listing",
-			"Event", listing.Event.Name,
-			"NumTickets", listing.NumTickets,
-			"Price", listing.TotalPriceInclFee().String(),
-			"OriginalPrice", listing.OriginalTicketPrice().String(),
-			"URL", listing.URL(),
-		)
+		description, err := listingTemplate.Execute(hamiltonListings[idx])
+		if err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("Found Hamilton ticket listing", "Listing", description)
 	}
 
 	// Filter ticket listings just by several filters
@@ -68,14 +75,10 @@ func main() {
 		filter.MinDiscount(0.1), // Discount of > 10%
 	)
 	for idx := 0; idx < len(coldplayListings); idx++ {
-		listing := coldplayListings[idx]
-		slog.Info(
-			"Found Coldplay ticket listing",
-			"Event", listing.Event.Name,
-			"NumTickets", listing.NumTickets,
-			"Price", listing.TotalPriceInclFee().String(),
-			"OriginalPrice", listing.OriginalTicketPrice().String(),
-			"URL", listing.URL(),
-		)
+		description, err := listingTemplate.Execute(coldplayListings[idx])
+		if err != nil {
+			log.Fatal(err)
+		}
+		slog.Info("Found Coldplay ticket listing", "Listing", description)
 	}
 }
@@ -0,0 +1,57 @@
+package twigots
+
+// FeeBreakdown is a breakdown of the prices and twickets fees of the tickets in a listing.
+//
+// Unlike the rounded per ticket prices of a listing (e.g. TicketPriceInclFee), the per ticket prices and fees
+// of a breakdown always add up to the totals. Any remainder from splitting a total between tickets is
+// distributed one minor unit at a time to the first tickets. See Price.Split.
+type FeeBreakdown struct {
+	// TotalPriceExclFee is the total price of all tickets, excluding fee.
+	TotalPriceExclFee Price
+	// TotalFee is the total twickets fee for all tickets.
+	TotalFee Price
+	// TotalPriceInclFee is the total price of all tickets, including fee.
+	TotalPriceInclFee Price
+
+	// TicketPricesExclFee are the prices of each ticket, excluding fee.
+	TicketPricesExclFee []Price
+	// TicketFees are the twickets fees of each ticket.
+	TicketFees []Price
+	// TicketPricesInclFee are the prices of each ticket, including fee.
+	// Each price is the sum of the ticket price excl fee and the ticket fee, added in the same way as
+	// TicketListing.TotalPriceInclFee.
+	TicketPricesInclFee []Price
+}
+
+// FeeBreakdown is a breakdown of the prices and twickets fees of each ticket in the listing.
+//
+// If the listing has no tickets, the per ticket prices will be empty.
+func (l TicketListing) FeeBreakdown() FeeBreakdown {
+	ticketPricesExclFee := l.TotalPriceExclFee.Split(l.NumTickets)
+	ticketFees := l.TwicketsFee.Split(l.NumTickets)
+
+	ticketPricesInclFee := make([]Price, len(ticketPricesExclFee))
+	for idx := 0; idx < len(ticketPricesExclFee); idx++ {
+		ticketPricesInclFee[idx] = addFee(ticketPricesExclFee[idx], ticketFees[idx])
+	}
+
+	return FeeBreakdown{
+		TotalPriceExclFee:   l.TotalPriceExclFee,
+		TotalFee:            l.TwicketsFee,
+		TotalPriceInclFee:   l.TotalPriceInclFee(),
+		TicketPricesExclFee: ticketPricesExclFee,
+		TicketFees:          ticketFees,
+		TicketPricesInclFee: ticketPricesInclFee,
+	}
+}
+
+// addFee adds a fee to a price excl fee. See TicketListing.TotalPriceInclFee.
+// If the currencies do not match, the fee amount is added as if it were in the currency of the price.
+func addFee(priceExclFee, fee Price) Price {
+	price, err := priceExclFee.Add(fee)
+	if err != nil {
+		// Currencies do not match, so fall back to the currency of the price excl fee
+		return priceExclFee.plus(fee)
+	}
+	return price
+}
@@ -0,0 +1,135 @@
+package twigots
+
+import (
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeeBreakdown(t *testing.T) {
+	listing := TicketListing{
+		NumTickets:        3,
+		TotalPriceExclFee: Price{Currency: CurrencyGBP, Amount: 100 * 100}, // £100
+		TwicketsFee:       Price{Currency: CurrencyGBP, Amount: 1001},      // £10.01
+	}
+
+	breakdown := listing.FeeBreakdown()
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 11001}, breakdown.TotalPriceInclFee)
+	require.Equal(t, []Price{
+		{Currency: CurrencyGBP, Amount: 3334},
+		{Currency: CurrencyGBP, Amount: 3333},
+		{Currency: CurrencyGBP, Amount: 3333},
+	}, breakdown.TicketPricesExclFee)
+	require.Equal(t, []Price{
+		{Currency: CurrencyGBP, Amount: 334},
+		{Currency: CurrencyGBP, Amount: 334},
+		{Currency: CurrencyGBP, Amount: 333},
+	}, breakdown.TicketFees)
+	require.Equal(t, []Price{
+		{Currency: CurrencyGBP, Amount: 3668},
+		{Currency: CurrencyGBP, Amount: 3667},
+		{Currency: CurrencyGBP, Amount: 3666},
+	}, breakdown.TicketPricesInclFee)
+
+	// Ticket price excl fee should not include the fee
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 3333}, listing.TicketPriceExclFee())
+
+	// Listings with no tickets have no per ticket prices
+	listing.NumTickets = 0
+	breakdown = listing.FeeBreakdown()
+	require.Empty(t, breakdown.TicketPricesExclFee)
+	require.Empty(t, breakdown.TicketFees)
+	require.Empty(t, breakdown.TicketPricesInclFee)
+}
+
+func TestTotalPriceInclFeeCurrencies(t *testing.T) {
+	// A zero fee with no currency takes the currency of the price
+	listing := TicketListing{TotalPriceExclFee: Price{Currency: CurrencyGBP, Amount: 1000}}
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 1000}, listing.TotalPriceInclFee())
+
+	listing = TicketListing{
+		TotalPriceExclFee: Price{Amount: 0},
+		TwicketsFee:       Price{Currency: CurrencyGBP, Amount: 100},
+	}
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 100}, listing.TotalPriceInclFee())
+
+	// A fee in a different currency falls back to the currency of the price
+	listing = TicketListing{
+		TotalPriceExclFee: Price{Currency: CurrencyGBP, Amount: 1000},
+		TwicketsFee:       Price{Currency: CurrencyEUR, Amount: 100},
+	}
+	_, err := listing.TotalPriceExclFee.Add(listing.TwicketsFee)
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 1100}, listing.TotalPriceInclFee())
+}
+
+func TestFeeBreakdownReconciles(t *testing.T) {
+	reconciles := func(numTickets uint8, totalPriceExclFee, twicketsFee uint32) bool {
+		listing := TicketListing{
+			NumTickets:        int(numTickets%20) + 1,
+			TotalPriceExclFee: Price{Currency: CurrencyGBP, Amount: int(totalPriceExclFee % 10_000_000)},
+			TwicketsFee:       Price{Currency: CurrencyGBP, Amount: int(twicketsFee % 1_000_000)},
+		}
+		breakdown := listing.FeeBreakdown()
+
+		if len(breakdown.TicketPricesExclFee) != listing.NumTickets ||
+			len(breakdown.TicketFees) != listing.NumTickets ||
+			len(breakdown.TicketPricesInclFee) != listing.NumTickets {
+			return false
+		}
+
+		// Per ticket amounts should add up to the totals
+		var sumExclFee, sumFee, sumInclFee int
+		for idx := 0; idx < listing.NumTickets; idx++ {
+			ticketPriceExclFee := breakdown.TicketPricesExclFee[idx]
+			ticketFee := breakdown.TicketFees[idx]
+			ticketPriceInclFee := breakdown.TicketPricesInclFee[idx]
+			if ticketPriceInclFee.Amount != ticketPriceExclFee.Amount+ticketFee.Amount {
+				return false
+			}
+
+			sumExclFee += ticketPriceExclFee.Amount
+			sumFee += ticketFee.Amount
+			sumInclFee += ticketPriceInclFee.Amount
+		}
+
+		return sumExclFee == breakdown.TotalPriceExclFee.Amount &&
+			sumFee == breakdown.TotalFee.Amount &&
+			sumInclFee == breakdown.TotalPriceInclFee.Amount &&
+			breakdown.TotalPriceInclFee.Amount == listing.TotalPriceExclFee.Amount+listing.TwicketsFee.Amount
+	}
+	require.NoError(t, quick.Check(reconciles, nil))
+}
+
+func TestPriceAccessorsMatchFeeBreakdown(t *testing.T) {
+	// The rounded per ticket prices should be within one minor unit of every exact per ticket price,
+	// except the price incl fee, which is within two (one from the price and one from the fee).
+	withinUnits := func(price Price, exactPrices []Price, units int) bool {
+		for _, exactPrice := range exactPrices {
+			if absInt(price.Amount-exactPrice.Amount) > units {
+				return false
+			}
+		}
+		return true
+	}
+
+	matches := func(numTickets uint8, totalPriceExclFee, twicketsFee uint32) bool {
+		listing := TicketListing{
+			NumTickets:        int(numTickets%20) + 1,
+			TotalPriceExclFee: Price{Currency: CurrencyGBP, Amount: int(totalPriceExclFee % 10_000_000)},
+			TwicketsFee:       Price{Currency: CurrencyGBP, Amount: int(twicketsFee % 1_000_000)},
+		}
+		breakdown := listing.FeeBreakdown()
+
+		return listing.TotalPriceInclFee() == breakdown.TotalPriceInclFee &&
+			withinUnits(listing.TicketPriceExclFee(), breakdown.TicketPricesExclFee, 1) &&
+			withinUnits(listing.TwicketsFeePerTicket(), breakdown.TicketFees, 1) &&
+			withinUnits(listing.TicketPriceInclFee(), breakdown.TicketPricesInclFee, 2) &&
+			absInt(listing.TicketPriceExclFee().Amount+listing.TwicketsFeePerTicket().Amount-
+				listing.TicketPriceInclFee().Amount) <= 1 &&
+			absInt(listing.TicketPriceInclFee().Amount*listing.NumTickets-
+				listing.TotalPriceInclFee().Amount) <= listing.NumTickets
+	}
+	require.NoError(t, quick.Check(matches, nil))
+}
@@ -0,0 +1,198 @@
+package filter
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+//go:embed aliases.json
+var defaultAliasesJSON string
+
+var (
+	// Default aliases, parsed on first use
+	defaultAliases     *Aliases
+	defaultAliasesOnce sync.Once
+
+	// Regular expression to match separators between segments of a listing event name
+	// e.g. "Taylor Swift | The Eras Tour" or "Coldplay: Music of the Spheres"
+	nameSegmentSeparatorRegex = regexp.MustCompile(`\s*(?:\||:|\s[-–—]\s)\s*`)
+)
+
+// Aliases is a dictionary of names (e.g. event or artist names) and their aliases
+// e.g. "Les Mis" for "Les Misérables" or "MCR" for "My Chemical Romance".
+//
+// Aliases work in both directions, so a name and all of its aliases are equivalent to each other.
+// Names are normalised before being looked up, so aliases that only differ in case, accents
+// or punctuation are not needed.
+//
+// Aliases must not be modified after they are first used.
+type Aliases struct {
+	// groups maps each normalised name to the group of names it is equivalent to
+	groups map[string]*aliasGroup
+}
+
+// aliasGroup is a group of equivalent names.
+type aliasGroup struct {
+	names           []aliasName
+	normalisedNames map[string]struct{}
+}
+
+// aliasName is a name in a group of aliases, and its normalised name.
+type aliasName struct {
+	name       string
+	normalised string
+}
+
+// NewAliases creates aliases from a map of names to their aliases.
+func NewAliases(aliases map[string][]string) *Aliases {
+	a := &Aliases{}
+	for name, nameAliases := range aliases {
+		a.Add(name, nameAliases...)
+	}
+	return a
+}
+
+// ParseAliases parses aliases from JSON.
+// JSON should be an object of names to an array of their aliases e.g. {"Les Misérables": ["Les Mis"]}.
+func ParseAliases(r io.Reader) (*Aliases, error) {
+	var aliases map[string][]string
+	err := json.NewDecoder(r).Decode(&aliases)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode aliases: %w", err)
+	}
+	return NewAliases(aliases), nil
+}
+
+// LoadAliases loads aliases from a JSON file. See ParseAliases for the file format.
+func LoadAliases(path string) (*Aliases, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open aliases file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseAliases(file)
+}
+
+// DefaultAliases returns the built-in aliases of common abbreviations of event and artist names.
+// The returned aliases must not be modified. Use MergeAliases to combine them with other aliases.
+func DefaultAliases() *Aliases {
+	defaultAliasesOnce.Do(func() {
+		aliases, err := ParseAliases(strings.NewReader(defaultAliasesJSON))
+		if err != nil {
+			// An error will never occur if the embedded aliases are valid.
+			// If an error does occur (due to an error in the aliases file), panic so we catch it.
+			panic(err)
+		}
+		defaultAliases = aliases
+	})
+	return defaultAliases
+}
+
+// MergeAliases merges multiple aliases into new aliases.
+// Groups of names that share a name are merged.
+func MergeAliases(aliases ...*Aliases) *Aliases {
+	merged := &Aliases{}
+	for _, a := range aliases {
+		if a == nil {
+			continue
+		}
+		for _, group := range a.uniqueGroups() {
+			names := make([]string, 0, len(group.names))
+			for _, name := range group.names {
+				names = append(names, name.name)
+			}
+			merged.Add(names[0], names[1:]...)
+		}
+	}
+	return merged
+}
+
+// Add adds a name and its aliases.
+// If the name or any of its aliases already have aliases, all of the aliases are merged.
+func (a *Aliases) Add(name string, aliases ...string) {
+	if a.groups == nil {
+		a.groups = make(map[string]*aliasGroup)
+	}
+
+	names := append([]string{name}, aliases...)
+
+	merged := &aliasGroup{normalisedNames: make(map[string]struct{})}
+	for _, name := range names {
+		existingGroup, ok := a.groups[normaliseString(name)]
+		if ok {
+			for _, existingName := range existingGroup.names {
+				merged.add(existingName.name)
+			}
+		}
+		merged.add(name)
+	}
+
+	for normalisedName := range merged.normalisedNames {
+		a.groups[normalisedName] = merged
+	}
+}
+
+// Lookup returns the aliases of a name, not including the name itself.
+// Returns nil if the name has no aliases.
+func (a *Aliases) Lookup(name string) []string {
+	if a == nil {
+		return nil
+	}
+
+	normalisedName := normaliseString(name)
+	group, ok := a.groups[normalisedName]
+	if !ok {
+		return nil
+	}
+
+	aliases := make([]string, 0, len(group.names)-1)
+	for _, alias := range group.names {
+		if alias.normalised != normalisedName {
+			aliases = append(aliases, alias.name)
+		}
+	}
+	if len(aliases) == 0 {
+		return nil
+	}
+	return aliases
+}
+
+// uniqueGroups returns each group of names once.
+func (a *Aliases) uniqueGroups() []*aliasGroup {
+	seen := make(map[*aliasGroup]struct{}, len(a.groups))
+	groups := make([]*aliasGroup, 0, len(a.groups))
+	for _, group := range a.groups {
+		if _, ok := seen[group]; ok {
+			continue
+		}
+		seen[group] = struct{}{}
+		groups = append(groups, group)
+	}
+	return groups
+}
+
+// add adds a name to the group, if an equivalent name is not already in the group.
+func (g *aliasGroup) add(name string) {
+	normalisedName := normaliseString(name)
+	if normalisedName == "" {
+		return
+	}
+	if _, ok := g.normalisedNames[normalisedName]; ok {
+		return
+	}
+	g.normalisedNames[normalisedName] = struct{}{}
+	g.names = append(g.names, aliasName{name: name, normalised: normalisedName})
+}
+
+// splitNameSegments splits a listing event name into its segments
+// e.g. "Taylor Swift | The Eras Tour" is split into "Taylor Swift" and "The Eras Tour".
+func splitNameSegments(name string) []string {
+	return nameSegmentSeparatorRegex.Split(strings.TrimSpace(name), -1)
+}
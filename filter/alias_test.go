@@ -0,0 +1,117 @@
+package filter
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAliasesLookup(t *testing.T) {
+	aliases := NewAliases(map[string][]string{
+		"Les Misérables": {"Les Mis", "Les Miz"},
+	})
+
+	// Aliases work in both directions
+	require.Equal(t, []string{"Les Mis", "Les Miz"}, aliases.Lookup("les miserables"))
+	require.ElementsMatch(t, []string{"Les Misérables", "Les Miz"}, aliases.Lookup("LES MIS"))
+	require.Nil(t, aliases.Lookup("Hamilton"))
+
+	// Groups that share a name are merged
+	aliases.Add("Les Miz", "Les Miserables The Musical")
+	require.ElementsMatch(
+		t,
+		[]string{"Les Mis", "Les Miz", "Les Miserables The Musical"},
+		aliases.Lookup("Les Misérables"),
+	)
+
+	// Nil aliases have no aliases
+	var nilAliases *Aliases
+	require.Nil(t, nilAliases.Lookup("Les Mis"))
+}
+
+func TestParseAliases(t *testing.T) {
+	aliases, err := ParseAliases(strings.NewReader(`{"My Chemical Romance": ["MCR"]}`))
+	require.NoError(t, err)
+	require.Equal(t, []string{"MCR"}, aliases.Lookup("My Chemical Romance"))
+
+	_, err = ParseAliases(strings.NewReader(`["MCR"]`))
+	require.Error(t, err)
+}
+
+func TestLoadAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.json")
+	err := os.WriteFile(path, []byte(`{"Nine Inch Nails": ["NIN"]}`), 0o600)
+	require.NoError(t, err)
+
+	aliases, err := LoadAliases(path)
+	require.NoError(t, err)
+	require.Equal(t, []string{"Nine Inch Nails"}, aliases.Lookup("NIN"))
+
+	merged := MergeAliases(DefaultAliases(), aliases)
+	require.Equal(t, []string{"Nine Inch Nails"}, merged.Lookup("NIN"))
+	require.Equal(t, []string{"My Chemical Romance"}, merged.Lookup("MCR"))
+}
+
+func TestEventNameWithAliases(t *testing.T) {
+	options := EventNameOptions{Aliases: DefaultAliases()}
+
+	testCases := []struct {
+		desiredEventName string
+		actualEventName  string
+		match            bool
+	}{
+		{"Les Mis", "Les Misérables", true},
+		{"Les Misérables", "Les Mis - Sondheim Theatre", true},
+		{"My Chemical Romance", "MCR", true},
+		{"My Chemical Romance Live", "MCR | Live", true},
+		{"MCR", "My Chemical Romance: Long Live The Black Parade", true},
+		{"MCR", "Manic Street Preachers", false},
+		{"Nine Inch Nails", "Nine Inch Nails", true},
+		{"Nine Inch Nails", "Oasis", false},
+	}
+
+	for _, testCase := range testCases {
+		listing := twigots.TicketListing{Event: twigots.Event{Name: testCase.actualEventName}}
+
+		predicate := EventNameWithOptions(testCase.desiredEventName, options)
+		require.Equal(
+			t, testCase.match, predicate(listing),
+			"match of %q to %q", testCase.desiredEventName, testCase.actualEventName,
+		)
+	}
+
+	// Without aliases, abbreviations do not match
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "MCR"}}
+	require.False(t, EventNameWithOptions("My Chemical Romance", EventNameOptions{})(listing))
+
+	// EventName uses the default aliases
+	require.True(t, EventName("My Chemical Romance", 0)(listing))
+}
+
+func TestEventNameWithLineup(t *testing.T) {
+	listing := twigots.TicketListing{
+		Event: twigots.Event{
+			Name: "Reading Festival 2025",
+			Lineup: []twigots.Lineup{
+				{Artist: twigots.Artist{Name: "Travis Scott"}},
+				{Artist: twigots.Artist{Name: "Chappell Roan"}},
+			},
+		},
+	}
+
+	require.False(t, EventNameWithOptions("Chappell Roan", EventNameOptions{})(listing))
+	require.True(t, EventNameWithOptions("Chappell Roan", EventNameOptions{MatchLineup: true})(listing))
+	require.False(t, EventNameWithOptions("Hozier", EventNameOptions{MatchLineup: true})(listing))
+
+	// Matching the lineup should not change the cached alias target names of the event name
+	options := EventNameOptions{Aliases: DefaultAliases(), MatchLineup: true}
+	targets := aliasTargetNames(DefaultAliases(), listing.Event.Name)
+	require.True(t, EventNameWithOptions("Chappell Roan", options)(listing))
+	require.True(t, EventNameWithOptions("Chappell Roan", options)(listing))
+	require.Equal(t, targets, aliasTargetNames(DefaultAliases(), listing.Event.Name))
+	require.Len(t, targets, 1)
+}
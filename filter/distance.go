@@ -0,0 +1,51 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/geo"
+)
+
+// WithinDistance creates a predicate that matches ticket listings with an event venue within the specified
+// distance (in kilometres) of an origin e.g. the coordinates of a user's postcode from geo.PostcodeCoordinates.
+//
+// The distance to a venue is calculated from the coordinates of its postcode. See twigots.Venue.Coordinates.
+// Listings with a venue that has no postcode, or an unknown postcode, will not match.
+//
+// Set maxDistanceKm to <=0 to match any distance.
+func WithinDistance(origin geo.Coordinates, maxDistanceKm float64) TicketListingPredicate {
+	// If no distance specified, match any distance
+	if maxDistanceKm <= 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		venueCoordinates, err := listing.Event.Venue.Coordinates()
+		return err == nil && origin.DistanceTo(venueCoordinates) <= maxDistanceKm
+	}
+}
+
+// ExplainWithinDistance creates an explainer for WithinDistance. See Evaluate.
+func ExplainWithinDistance(origin geo.Coordinates, maxDistanceKm float64) TicketListingExplainer {
+	// If no distance specified, match any distance
+	if maxDistanceKm <= 0 {
+		return alwaysExplainer("WithinDistance")
+	}
+
+	return newExplainer("WithinDistance", func(listing twigots.TicketListing) (bool, string) {
+		venueCoordinates, err := listing.Event.Venue.Coordinates()
+		if err != nil {
+			return false, fmt.Sprintf("venue location unknown: %s", err)
+		}
+
+		distance := origin.DistanceTo(venueCoordinates)
+		return compare("distance", distance <= maxDistanceKm, formatDistance(distance), "<=", formatDistance(maxDistanceKm))
+	})
+}
+
+// formatDistance formats a distance in kilometres as a string with 1 decimal place e.g. 12.3km
+func formatDistance(distanceKm float64) string {
+	return strconv.FormatFloat(distanceKm, 'f', 1, 64) + "km"
+}
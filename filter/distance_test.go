@@ -0,0 +1,29 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithinDistancePredicate(t *testing.T) {
+	london := geo.Coordinates{Latitude: 51.5074, Longitude: -0.1278}
+	londonListing := twigots.TicketListing{Event: twigots.Event{Venue: twigots.Venue{Postcode: "E20 2ST"}}}
+	manchesterListing := twigots.TicketListing{Event: twigots.Event{Venue: twigots.Venue{Postcode: "M1 1AE"}}}
+	unknownListing := twigots.TicketListing{Event: twigots.Event{Venue: twigots.Venue{Name: "Somewhere"}}}
+
+	predicate := WithinDistance(london, 50)
+	require.True(t, predicate(londonListing))
+	require.False(t, predicate(manchesterListing))
+	require.False(t, predicate(unknownListing))
+
+	result := Evaluate(manchesterListing, ExplainWithinDistance(london, 50))
+	require.Regexp(t, `^distance 2\d\d\.\dkm > 50\.0km$`, result.Verdicts[0].Reason)
+
+	require.True(t, WithinDistance(london, 300)(manchesterListing))
+
+	// No distance should match anything
+	require.True(t, WithinDistance(london, 0)(unknownListing))
+}
@@ -0,0 +1,75 @@
+package filter
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventNameExcludeTerms(t *testing.T) {
+	hamilton := EventNameWithOptions("Hamilton", EventNameOptions{ExcludeTerms: []string{"FC"}})
+	coldplay := EventNameWithOptions("Coldplay", EventNameOptions{ExcludeTerms: []string{"Tribute", "Experience"}})
+
+	testCases := []struct {
+		predicate       TicketListingPredicate
+		actualEventName string
+		match           bool
+	}{
+		{hamilton, "Hamilton", true},
+		{hamilton, "Hamilton Academical FC v Raith Rovers", false},
+		// Terms only match whole words
+		{hamilton, "Hamilton - Victoria Palace Theatre (FCFS seating)", true},
+		{coldplay, "Coldplay: Music of the Spheres World Tour", true},
+		{coldplay, "Coldplay Tribute - Coldplace", false},
+		{coldplay, "The Coldplay Experience", false},
+	}
+
+	for _, testCase := range testCases {
+		listing := twigots.TicketListing{Event: twigots.Event{Name: testCase.actualEventName}}
+		require.Equal(t, testCase.match, testCase.predicate(listing), "match of %q", testCase.actualEventName)
+	}
+}
+
+func TestEventNamePatterns(t *testing.T) {
+	predicate := EventNameWithOptions("Hamilton", EventNameOptions{
+		IncludePatterns: []*regexp.Regexp{regexp.MustCompile(`^hamilton`)},
+		ExcludePatterns: []*regexp.Regexp{regexp.MustCompile(`\bv\b`)},
+	})
+
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "Hamilton"}}
+	require.True(t, predicate(listing))
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Hamilton Academical FC v Raith Rovers"}}
+	require.False(t, predicate(listing))
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Hamilton Sing-Along"}}
+	require.True(t, predicate(listing))
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Sing-Along Hamilton"}}
+	require.False(t, predicate(listing))
+}
+
+func TestEventNameExclusionExplain(t *testing.T) {
+	options := EventNameOptions{
+		ExcludeTerms:    []string{"Tribute"},
+		ExcludePatterns: []*regexp.Regexp{regexp.MustCompile(`^the`)},
+		IncludePatterns: []*regexp.Regexp{regexp.MustCompile(`tour$`)},
+	}
+	matcher := NewNameMatcherWithOptions("Coldplay", options)
+
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "Coldplay Tribute"}}
+	require.Equal(t, `EventName contains excluded term "tribute"`, matcher.Explain(listing).String())
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Coldplay Live"}}
+	require.Equal(t, `EventName "coldplay live" does not match any include pattern`, matcher.Explain(listing).String())
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Coldplay: Music of the Spheres Tour"}}
+	require.Equal(t, "EventName similarity 1.00 >= 0.90", matcher.Explain(listing).String())
+
+	// Exclusions apply even if no event name is specified
+	matcher = NewNameMatcherWithOptions("", options)
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Coldplay Tribute Tour"}}
+	require.False(t, matcher.Matches(listing))
+}
@@ -0,0 +1,147 @@
+package filter
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// TicketListingExplainer is a function that evaluates a TicketListing and explains whether or not the listing
+// satisfies a condition, and why.
+//
+// Each predicate in this package has an explainer e.g. ExplainEventName for EventName. See Evaluate.
+type TicketListingExplainer func(twigots.TicketListing) Verdict
+
+// Predicate returns a predicate that matches ticket listings the explainer matches.
+func (e TicketListingExplainer) Predicate() TicketListingPredicate {
+	return func(listing twigots.TicketListing) bool {
+		return e(listing).Matched
+	}
+}
+
+// Named creates a named explainer from a predicate, so predicates without an explainer
+// can be identified when explaining results. The reason is either "matched" or "not matched".
+func Named(name string, predicate TicketListingPredicate) TicketListingExplainer {
+	return newExplainer(name, func(listing twigots.TicketListing) (bool, string) {
+		if predicate(listing) {
+			return true, "matched"
+		}
+		return false, "not matched"
+	})
+}
+
+// newExplainer creates a named explainer.
+// explain should return whether the listing satisfies the predicate, and the reason why.
+func newExplainer(name string, explain func(twigots.TicketListing) (bool, string)) TicketListingExplainer {
+	return func(listing twigots.TicketListing) Verdict {
+		matched, reason := explain(listing)
+		return Verdict{Predicate: name, Matched: matched, Reason: reason}
+	}
+}
+
+// Verdict is the result of evaluating a single predicate against a ticket listing.
+type Verdict struct {
+	// Predicate is the name of the predicate e.g. EventName.
+	Predicate string
+	// Matched is whether the ticket listing satisfied the predicate.
+	Matched bool
+	// Reason is why the ticket listing did or did not satisfy the predicate,
+	// including the relevant value e.g. "similarity 0.82 < 0.90".
+	Reason string
+}
+
+// String returns the verdict as a string e.g. "EventName similarity 0.82 < 0.90".
+func (v Verdict) String() string {
+	if v.Reason == "" {
+		return v.Predicate
+	}
+	return fmt.Sprintf("%s %s", v.Predicate, v.Reason)
+}
+
+// Result is the result of evaluating predicates against a ticket listing.
+type Result struct {
+	Listing twigots.TicketListing
+	// Matched is whether the ticket listing satisfied all of the predicates.
+	Matched bool
+	// Verdicts of each predicate, in the order the predicates were provided.
+	Verdicts []Verdict
+}
+
+// Rejections are the verdicts of the predicates the ticket listing did not satisfy.
+func (r Result) Rejections() []Verdict {
+	rejections := make([]Verdict, 0, len(r.Verdicts))
+	for _, verdict := range r.Verdicts {
+		if !verdict.Matched {
+			rejections = append(rejections, verdict)
+		}
+	}
+	return rejections
+}
+
+// String returns the result as a multi-line string, with the verdict of each predicate on its own line.
+func (r Result) String() string {
+	var builder strings.Builder
+
+	outcome := "rejected"
+	if r.Matched {
+		outcome = "matched"
+	}
+	fmt.Fprintf(&builder, "%s (%s): %s", r.Listing.Event.Name, r.Listing.Id, outcome)
+
+	for _, verdict := range r.Verdicts {
+		mark := "✗"
+		if verdict.Matched {
+			mark = "✓"
+		}
+		fmt.Fprintf(&builder, "\n  %s %s", mark, verdict)
+	}
+
+	return builder.String()
+}
+
+// Evaluate evaluates all of the explainers against a ticket listing, explaining the verdict of each.
+//
+// Unlike TicketListingMatchesAllPredicates, all explainers are evaluated, even after one is not satisfied.
+// Use Named to evaluate predicates that do not have an explainer.
+//
+// The result is matched if no explainers are provided.
+func Evaluate(listing twigots.TicketListing, explainers ...TicketListingExplainer) Result {
+	result := Result{
+		Listing:  listing,
+		Matched:  true,
+		Verdicts: make([]Verdict, 0, len(explainers)),
+	}
+
+	for _, explainer := range explainers {
+		verdict := explainer(listing)
+		if !verdict.Matched {
+			result.Matched = false
+		}
+		result.Verdicts = append(result.Verdicts, verdict)
+	}
+
+	return result
+}
+
+// EvaluateTicketListings evaluates all of the explainers against each ticket listing. See Evaluate.
+func EvaluateTicketListings(listings []twigots.TicketListing, explainers ...TicketListingExplainer) []Result {
+	results := make([]Result, 0, len(listings))
+	for idx := 0; idx < len(listings); idx++ {
+		results = append(results, Evaluate(listings[idx], explainers...))
+	}
+	return results
+}
+
+// PrintEvaluations evaluates all of the explainers against each ticket listing,
+// and writes the results to w. This is useful for debugging why listings did or did not match.
+func PrintEvaluations(w io.Writer, listings []twigots.TicketListing, explainers ...TicketListingExplainer) error {
+	for _, result := range EvaluateTicketListings(listings, explainers...) {
+		_, err := fmt.Fprintln(w, result)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package filter
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/geo"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvaluate(t *testing.T) {
+	// £95 per ticket incl fee
+	listing := twigots.TicketListing{
+		Id:                "123",
+		Event:             twigots.Event{Name: "Oasish"},
+		NumTickets:        2,
+		TotalPriceExclFee: gbp(180 * 100),
+		TwicketsFee:       gbp(10 * 100),
+	}
+
+	result := Evaluate(
+		listing,
+		ExplainEventName("Oasis", 0.9),
+		ExplainMaxTicketPriceInclFee(gbp(80*100)),
+		ExplainNumTickets(2),
+		Named("Custom", func(_ twigots.TicketListing) bool { return true }),
+	)
+	require.False(t, result.Matched)
+	require.Len(t, result.Verdicts, 4)
+
+	require.Equal(t, "EventName", result.Verdicts[0].Predicate)
+	require.False(t, result.Verdicts[0].Matched)
+	require.Equal(t, "EventName similarity 0.83 < 0.90", result.Verdicts[0].String())
+
+	require.False(t, result.Verdicts[1].Matched)
+	require.Equal(t, "MaxTicketPriceInclFee ticket price incl fee £95.00 > £80.00", result.Verdicts[1].String())
+
+	require.True(t, result.Verdicts[2].Matched)
+	require.Equal(t, "NumTickets num tickets 2 == 2", result.Verdicts[2].String())
+
+	// Predicates without an explainer can be named
+	require.True(t, result.Verdicts[3].Matched)
+	require.Equal(t, "Custom matched", result.Verdicts[3].String())
+
+	rejections := result.Rejections()
+	require.Len(t, rejections, 2)
+	require.Equal(t, "EventName", rejections[0].Predicate)
+	require.Equal(t, "MaxTicketPriceInclFee", rejections[1].Predicate)
+
+	// Evaluate should agree with matching all predicates
+	result = Evaluate(listing, ExplainNumTickets(2), Named("Cheap", MaxTotalPriceInclFee(gbp(200*100))))
+	require.True(t, result.Matched)
+	require.Equal(t, "Cheap matched", result.Verdicts[1].String())
+	require.True(t, TicketListingMatchesAllPredicates(
+		listing,
+		ExplainNumTickets(2).Predicate(),
+		MaxTotalPriceInclFee(gbp(200*100)),
+	))
+}
+
+func TestPredicatesMatchExplainers(t *testing.T) {
+	london := geo.Coordinates{Latitude: 51.5074, Longitude: -0.1278}
+	converter, err := twigots.NewStaticConverter(twigots.CurrencyGBP, map[twigots.Currency]float64{
+		twigots.CurrencyEUR: 1.25,
+	})
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		predicate TicketListingPredicate
+		explainer TicketListingExplainer
+	}{
+		"EventRegion":       {EventRegion(twigots.RegionLondon), ExplainEventRegion(twigots.RegionLondon)},
+		"NumTickets":        {NumTickets(2), ExplainNumTickets(2)},
+		"MinNumTickets":     {MinNumTickets(2), ExplainMinNumTickets(2)},
+		"MaxNumTickets":     {MaxNumTickets(2), ExplainMaxNumTickets(2)},
+		"NumTicketsBetween": {NumTicketsBetween(4, 2), ExplainNumTicketsBetween(4, 2)},
+		"CanBuy":            {CanBuy(2), ExplainCanBuy(2)},
+		"MinDiscount":       {MinDiscount(0.1), ExplainMinDiscount(0.1)},
+		"MinTicketPriceExclFee": {
+			MinTicketPriceExclFee(gbp(40 * 100)),
+			ExplainMinTicketPriceExclFee(gbp(40 * 100)),
+		},
+		"MaxTicketPriceInclFee": {
+			MaxTicketPriceInclFee(gbp(50*100), WithConverter(converter)),
+			ExplainMaxTicketPriceInclFee(gbp(50*100), WithConverter(converter)),
+		},
+		"MaxTotalPriceExclFee": {MaxTotalPriceExclFee(gbp(90 * 100)), ExplainMaxTotalPriceExclFee(gbp(90 * 100))},
+		"AtOrBelowFaceValue":   {AtOrBelowFaceValue(), ExplainAtOrBelowFaceValue()},
+		"MaxFeePercentage":     {MaxFeePercentage(0.1), ExplainMaxFeePercentage(0.1)},
+		"TicketType":           {TicketType(TicketTypeSeated), ExplainTicketType(TicketTypeSeated)},
+		"SeatAssigned":         {SeatAssigned(), ExplainSeatAssigned()},
+		"AcceptsOffers":        {AcceptsOffers(), ExplainAcceptsOffers()},
+		"Section":              {Section("101-105"), ExplainSection("101-105")},
+		"NotRow":               {NotRow("A-C"), ExplainNotRow("A-C")},
+		"WithinDistance":       {WithinDistance(london, 50), ExplainWithinDistance(london, 50)},
+	}
+
+	listings := []twigots.TicketListing{
+		{},
+		{
+			NumTickets:         2,
+			TotalPriceExclFee:  gbp(80 * 100),
+			TwicketsFee:        gbp(5 * 100),
+			OriginalTotalPrice: gbp(100 * 100),
+			TicketType:         "Reserved Seating",
+			SeatAssigned:       true,
+			Section:            "103",
+			Row:                "B",
+			Event: twigots.Event{Venue: twigots.Venue{
+				Postcode: "E20 2ST",
+				Location: twigots.Location{Region: twigots.RegionLondon},
+			}},
+		},
+		{
+			NumTickets:               3,
+			TotalPriceExclFee:        twigots.Price{Currency: twigots.CurrencyEUR, Amount: 150 * 100},
+			TwicketsFee:              twigots.Price{Currency: twigots.CurrencyEUR, Amount: 30 * 100},
+			OriginalTotalPrice:       gbp(100 * 100),
+			TicketType:               "PITCH STANDING & UNRESERVED SEATS",
+			SellerWillConsiderOffers: true,
+			Section:                  "Upper Tier",
+			Row:                      "F",
+			Event:                    twigots.Event{Venue: twigots.Venue{Postcode: "M1 1AE"}},
+		},
+	}
+
+	for name, test := range tests {
+		for idx, listing := range listings {
+			require.Equal(t, test.explainer(listing).Matched, test.predicate(listing), "%s of listing %d", name, idx)
+		}
+	}
+}
+
+func TestCompareUnsupportedOperator(t *testing.T) {
+	matched, reason := compareInts("num tickets", 2, "~", 2)
+	require.False(t, matched)
+	require.Equal(t, `operator "~" is not supported`, reason)
+
+	matched, reason = compare("price", true, "£10.00", "~", "£20.00")
+	require.False(t, matched)
+	require.Equal(t, `operator "~" is not supported`, reason)
+}
+
+func TestPrintEvaluations(t *testing.T) {
+	listing := twigots.TicketListing{
+		Id:         "123",
+		Event:      twigots.Event{Name: "Oasis"},
+		NumTickets: 2,
+	}
+
+	var buffer bytes.Buffer
+	err := PrintEvaluations(
+		&buffer,
+		[]twigots.TicketListing{listing},
+		ExplainEventName("Oasis", 1),
+		ExplainNumTickets(3),
+	)
+	require.NoError(t, err)
+
+	expected := "Oasis (123): rejected\n" +
+		"  ✓ EventName similarity 1.00 >= 1.00\n" +
+		"  ✗ NumTickets num tickets 2 != 3\n"
+	require.Equal(t, expected, buffer.String())
+}
@@ -0,0 +1,489 @@
+package filter
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+const (
+	// Maximum number of normalised names to cache before the cache is cleared
+	maxNormalisedNameCacheSize = 10000
+
+	// Tolerance used when pruning using similarity upper bounds,
+	// to account for float32 rounding in word similarity calculations
+	similarityBoundTolerance = 1e-6
+)
+
+// normalisedNameCache is a cache of names to their normalised names.
+// It is shared between all name matchers, so each listing name only needs normalising once.
+var normalisedNameCache = struct {
+	sync.RWMutex
+	names map[string]normalisedName
+}{names: make(map[string]normalisedName)}
+
+// aliasTargetNameCache is a cache of names to their alias target names (see aliasTargetNames), keyed by the
+// aliases used and the name. It is shared between all name matchers and watchlists, so the aliases of each listing
+// name only need looking up once.
+var aliasTargetNameCache = struct {
+	sync.RWMutex
+	names map[aliasTargetNameKey][]normalisedName
+}{names: make(map[aliasTargetNameKey][]normalisedName)}
+
+// aliasTargetNameKey is the key of a name in the alias target name cache.
+type aliasTargetNameKey struct {
+	aliases *Aliases
+	name    string
+}
+
+// normalisedName is a normalised name split into words.
+type normalisedName struct {
+	words []string
+	// padded is the words joined with spaces, with a space on either side
+	padded string
+	// phonetics are the primary and alternate Double Metaphone codes of each word
+	phonetics [][2]string
+}
+
+// NameMatcher matches ticket listings with an event name matching a desired event name.
+// Use NameMatcher.Matches as a TicketListingPredicate, and NameMatcher.Explain as a TicketListingExplainer.
+//
+// NameMatcher returns the same results as EventName (or EventNameWithOptions if created with options),
+// but normalises and tokenises the desired event name once, caches normalised listing event names, and avoids
+// calculating similarity where a listing cannot possibly match. This makes it much faster when matching large
+// numbers of listings.
+//
+// A NameMatcher is safe for concurrent use.
+type NameMatcher struct {
+	eventName string
+	// names are the normalised event name, followed by its normalised aliases
+	names             []normalisedName
+	minimumSimilarity float64
+	strategy          SimilarityStrategy
+	aliases           *Aliases
+	matchLineup       bool
+
+	// excludeTerms are the normalised exclude terms, with a space on either side
+	excludeTerms    []string
+	includePatterns []*regexp.Regexp
+	excludePatterns []*regexp.Regexp
+
+	bufferPool sync.Pool
+}
+
+// NewNameMatcher creates a name matcher that matches ticket listings with an event name matching the one specified.
+//
+// minimumSimilarity and aliases are handled in the same way as EventName.
+func NewNameMatcher(eventName string, minimumSimilarity float64) *NameMatcher {
+	return NewNameMatcherWithOptions(eventName, EventNameOptions{
+		MinimumSimilarity: minimumSimilarity,
+		Aliases:           DefaultAliases(),
+	})
+}
+
+// NewNameMatcherWithOptions creates a name matcher that matches ticket listings with an event name matching
+// the one specified, using the options specified. See EventNameWithOptions.
+func NewNameMatcherWithOptions(eventName string, options EventNameOptions) *NameMatcher {
+	minimumSimilarity := options.MinimumSimilarity
+
+	// Use default similarity if not specified or negative
+	if minimumSimilarity <= 0 {
+		minimumSimilarity = DefaultEventNameSimilarity
+	}
+
+	// Clamp similarity to maximum of 1.0
+	if minimumSimilarity > 1 {
+		minimumSimilarity = 1.0
+	}
+
+	// Get event name and its aliases
+	names := []normalisedName{newNormalisedName(eventName)}
+	for _, alias := range options.Aliases.Lookup(eventName) {
+		names = append(names, newNormalisedName(alias))
+	}
+
+	// Get normalised exclude terms
+	excludeTerms := make([]string, 0, len(options.ExcludeTerms))
+	for _, term := range options.ExcludeTerms {
+		normalisedTerm := normaliseString(term)
+		if normalisedTerm != "" {
+			excludeTerms = append(excludeTerms, " "+normalisedTerm+" ")
+		}
+	}
+
+	return &NameMatcher{
+		eventName:         eventName,
+		names:             names,
+		minimumSimilarity: minimumSimilarity,
+		strategy:          options.SimilarityStrategy,
+		aliases:           options.Aliases,
+		matchLineup:       options.MatchLineup,
+		excludeTerms:      excludeTerms,
+		includePatterns:   options.IncludePatterns,
+		excludePatterns:   options.ExcludePatterns,
+	}
+}
+
+// Similarity calculates the similarity of the matcher event name to a name.
+// If the matcher has no aliases, this is identical to calling NameSimilarity with the matcher event name.
+// Otherwise, the highest similarity of the matcher event name or its aliases to the name or its aliases is returned.
+func (m *NameMatcher) Similarity(name string) float64 {
+	similarity, _ := m.bestSimilarity(m.targetNames(name), false)
+	return similarity
+}
+
+// Matches returns whether a ticket listing has an event name matching the matcher event name.
+func (m *NameMatcher) Matches(listing twigots.TicketListing) bool {
+	if _, excluded := m.exclusionReason(listing); excluded {
+		return false
+	}
+
+	// If no event name specified, match any event
+	if m.eventName == "" {
+		return true
+	}
+
+	_, matched := m.bestSimilarity(m.listingTargetNames(listing), true)
+	return matched
+}
+
+// Explain explains whether a ticket listing has an event name matching the matcher event name, and why.
+func (m *NameMatcher) Explain(listing twigots.TicketListing) Verdict {
+	if reason, excluded := m.exclusionReason(listing); excluded {
+		return Verdict{Predicate: "EventName", Matched: false, Reason: reason}
+	}
+
+	// If no event name specified, match any event
+	if m.eventName == "" {
+		return Verdict{Predicate: "EventName", Matched: true, Reason: "not set, matches any listing"}
+	}
+
+	similarity, _ := m.bestSimilarity(m.listingTargetNames(listing), false)
+	matched, reason := compare(
+		"similarity",
+		similarity >= m.minimumSimilarity,
+		formatSimilarity(similarity), ">=", formatSimilarity(m.minimumSimilarity),
+	)
+	return Verdict{Predicate: "EventName", Matched: matched, Reason: reason}
+}
+
+// exclusionReason checks whether a ticket listing is excluded from matching by the matcher exclude terms,
+// include patterns or exclude patterns, returning the reason if it is.
+func (m *NameMatcher) exclusionReason(listing twigots.TicketListing) (string, bool) {
+	if len(m.excludeTerms) == 0 && len(m.includePatterns) == 0 && len(m.excludePatterns) == 0 {
+		return "", false
+	}
+
+	name := getNormalisedName(listing.Event.Name)
+	for _, term := range m.excludeTerms {
+		if strings.Contains(name.padded, term) {
+			return fmt.Sprintf("contains excluded term %q", strings.TrimSpace(term)), true
+		}
+	}
+
+	normalisedName := strings.TrimSpace(name.padded)
+	for _, pattern := range m.excludePatterns {
+		if pattern.MatchString(normalisedName) {
+			return fmt.Sprintf("%q matches exclude pattern %q", normalisedName, pattern), true
+		}
+	}
+
+	if len(m.includePatterns) == 0 {
+		return "", false
+	}
+	for _, pattern := range m.includePatterns {
+		if pattern.MatchString(normalisedName) {
+			return "", false
+		}
+	}
+	return fmt.Sprintf("%q does not match any include pattern", normalisedName), true
+}
+
+// targetNames gets the normalised names to match the matcher names against for a name.
+// If the matcher has aliases, the name is split into segments, and a name is added for each alias of each segment,
+// with the segment replaced by the alias.
+func (m *NameMatcher) targetNames(name string) []normalisedName {
+	return aliasTargetNames(m.aliases, name)
+}
+
+// aliasTargetNames gets the normalised names of a name and, if aliases is not nil, the name with each of its segments
+// replaced by each of the segment aliases, using the cache if possible. See NameMatcher.targetNames.
+//
+// The returned names are shared, so must not be modified. Appending to them is safe.
+func aliasTargetNames(aliases *Aliases, name string) []normalisedName {
+	if aliases == nil {
+		return []normalisedName{getNormalisedName(name)}
+	}
+
+	key := aliasTargetNameKey{aliases: aliases, name: name}
+	aliasTargetNameCache.RLock()
+	targets, ok := aliasTargetNameCache.names[key]
+	aliasTargetNameCache.RUnlock()
+	if ok {
+		return targets
+	}
+
+	targets = newAliasTargetNames(aliases, name)
+
+	aliasTargetNameCache.Lock()
+	if len(aliasTargetNameCache.names) >= maxNormalisedNameCacheSize {
+		clear(aliasTargetNameCache.names)
+	}
+	aliasTargetNameCache.names[key] = targets
+	aliasTargetNameCache.Unlock()
+
+	return targets
+}
+
+// newAliasTargetNames gets the normalised names of a name, and the name with each of its segments
+// replaced by each of the segment aliases. See aliasTargetNames.
+func newAliasTargetNames(aliases *Aliases, name string) []normalisedName {
+	targets := []normalisedName{getNormalisedName(name)}
+
+	segments := splitNameSegments(name)
+	for idx, segment := range segments {
+		for _, alias := range aliases.Lookup(segment) {
+			aliasSegments := slices.Clone(segments)
+			aliasSegments[idx] = alias
+			targets = append(targets, getNormalisedName(strings.Join(aliasSegments, " ")))
+		}
+	}
+
+	// Clip so appending to the shared names always copies them
+	return slices.Clip(targets)
+}
+
+// listingTargetNames gets the normalised names to match the matcher names against for a ticket listing.
+// If the matcher matches the lineup, this includes the names of the artists in the event lineup.
+func (m *NameMatcher) listingTargetNames(listing twigots.TicketListing) []normalisedName {
+	targets := m.targetNames(listing.Event.Name)
+	if !m.matchLineup {
+		return targets
+	}
+
+	for _, lineup := range listing.Event.Lineup {
+		targets = append(targets, m.targetNames(lineup.Artist.Name)...)
+	}
+
+	return targets
+}
+
+// bestSimilarity calculates the highest similarity of the matcher names to the target names, and whether it is
+// at least the minimum similarity. If prune is true, calculation will stop as soon as it is known whether the
+// minimum similarity is reached, in which case the returned similarity will not necessarily be the highest.
+func (m *NameMatcher) bestSimilarity(targets []normalisedName, prune bool) (float64, bool) {
+	var bestSimilarity float64
+	var matched bool
+	for _, name := range m.names {
+		for _, target := range targets {
+			similarity, nameMatched := m.similarity(name, target, prune)
+			if nameMatched && prune {
+				return similarity, true
+			}
+			bestSimilarity = max(bestSimilarity, similarity)
+			matched = matched || nameMatched
+		}
+	}
+	return bestSimilarity, matched
+}
+
+// similarity calculates the similarity of a matcher name to a target name using the matcher similarity strategy,
+// and whether it is at least the minimum similarity. If prune is true, calculation will stop as soon as it is known
+// the minimum similarity cannot be reached, in which case the returned similarity will be 0.
+func (m *NameMatcher) similarity(name, target normalisedName, prune bool) (float64, bool) {
+	numSubWords := len(name.words)
+	numTargetWords := len(target.words)
+
+	// If both or one string has no words, exit early
+	if numSubWords == 0 && numTargetWords == 0 {
+		return 1, true
+	}
+	if numSubWords == 0 || numTargetWords == 0 {
+		return 0, false
+	}
+
+	// If the words appear in the target in order, every word matches exactly
+	if strings.Contains(target.padded, name.padded) {
+		return 1, true
+	}
+
+	// Prune using an upper bound on word similarities based only on word lengths.
+	// The edit distance between two words is always at least the difference in their lengths.
+	// This does not apply to phonetic similarity, as words that sound the same can have very different lengths.
+	if prune && !m.strategy.usesPhonetics() &&
+		lengthSimilarityBound(name.words, target.words) < m.minimumSimilarity-similarityBoundTolerance {
+		return 0, false
+	}
+
+	// Get a buffer to hold the spelling and phonetic word similarity matrices,
+	// and two rows of the alignment matrix
+	numSimilarities := numSubWords * numTargetWords
+	buffer := m.getBuffer(2*numSimilarities + 2*(numTargetWords+1))
+	defer m.bufferPool.Put(buffer)
+	spellingSimilarities := (*buffer)[:numSimilarities]
+	phoneticSimilarities := (*buffer)[numSimilarities : 2*numSimilarities]
+	rows := (*buffer)[2*numSimilarities:]
+
+	// Calculate word similarities, and an upper bound on similarity,
+	// assuming each word matches its most similar target word
+	var similarityBound float64
+	if m.strategy.usesSpelling() {
+		similarityBound = fillWordSimilarities(
+			spellingSimilarities, numSubWords, numTargetWords,
+			func(i, j int) float64 {
+				return wordSimilarity(name.words[i], target.words[j])
+			},
+		)
+	}
+	if m.strategy.usesPhonetics() {
+		phoneticBound := fillWordSimilarities(
+			phoneticSimilarities, numSubWords, numTargetWords,
+			func(i, j int) float64 {
+				return phoneticWordSimilarity(name.words[i], target.words[j], name.phonetics[i], target.phonetics[j])
+			},
+		)
+		similarityBound = max(similarityBound, phoneticBound)
+	}
+
+	// Prune using the upper bound on similarity
+	if prune && similarityBound < m.minimumSimilarity-similarityBoundTolerance {
+		return 0, false
+	}
+
+	var similarity float64
+	switch m.strategy {
+	case SimilarityTokenSet:
+		similarity = matchWordSets(spellingSimilarities, numSubWords, numTargetWords)
+	case SimilarityPhonetic:
+		similarity = alignWords(phoneticSimilarities, numSubWords, numTargetWords, rows)
+	case SimilarityCombined:
+		similarity = maxUtil(
+			alignWords(spellingSimilarities, numSubWords, numTargetWords, rows),
+			matchWordSets(spellingSimilarities, numSubWords, numTargetWords),
+			alignWords(phoneticSimilarities, numSubWords, numTargetWords, rows),
+		)
+	default:
+		similarity = alignWords(spellingSimilarities, numSubWords, numTargetWords, rows)
+	}
+
+	return similarity, similarity >= m.minimumSimilarity
+}
+
+// fillWordSimilarities fills a flat numSubWords x numTargetWords matrix with word similarities, and returns the
+// average of the highest similarity of each substring word (an upper bound on the similarity of the words).
+func fillWordSimilarities(
+	similarities []float64,
+	numSubWords, numTargetWords int,
+	wordSimilarity func(i, j int) float64,
+) float64 {
+	var bound float64
+	for i := 0; i < numSubWords; i++ {
+		var maxWordSimilarity float64
+		for j := 0; j < numTargetWords; j++ {
+			similarity := wordSimilarity(i, j)
+			similarities[i*numTargetWords+j] = similarity
+			maxWordSimilarity = max(maxWordSimilarity, similarity)
+		}
+		bound += maxWordSimilarity
+	}
+	return bound / float64(numSubWords)
+}
+
+// lengthSimilarityBound calculates an upper bound of the similarity of words to target words,
+// using only the length of words.
+func lengthSimilarityBound(subWords, targetWords []string) float64 {
+	var bound float64
+	for _, subWord := range subWords {
+		var maxWordBound float64
+		for _, targetWord := range targetWords {
+			minLength := min(len(subWord), len(targetWord))
+			maxLength := max(len(subWord), len(targetWord))
+			maxWordBound = max(maxWordBound, float64(minLength)/float64(maxLength))
+		}
+		bound += maxWordBound
+	}
+	return bound / float64(len(subWords))
+}
+
+// getBuffer gets a zeroed buffer of the specified size from the pool.
+func (m *NameMatcher) getBuffer(size int) *[]float64 {
+	buffer, ok := m.bufferPool.Get().(*[]float64)
+	if !ok || cap(*buffer) < size {
+		newBuffer := make([]float64, size)
+		return &newBuffer
+	}
+	*buffer = (*buffer)[:size]
+	clear(*buffer)
+	return buffer
+}
+
+// alignWords aligns words using the modified Smith-Waterman algorithm used in substringSimilarity,
+// using precalculated word similarities. similarities is a flat numSubWords x numTargetWords matrix.
+// Only two rows of the alignment matrix are kept in memory, using rows which must be a slice of
+// length 2 x (numTargetWords + 1).
+func alignWords(similarities []float64, numSubWords, numTargetWords int, rows []float64) float64 {
+	clear(rows)
+	previousRow := rows[:numTargetWords+1]
+	currentRow := rows[numTargetWords+1 : 2*(numTargetWords+1)]
+
+	for i := 1; i <= numSubWords; i++ {
+		for j := 1; j <= numTargetWords; j++ {
+			similarity := similarities[(i-1)*numTargetWords+(j-1)]
+
+			matchScore := previousRow[j-1] + similarity
+			deleteScore := previousRow[j] - substringSimilarityGapPenalty
+			insertScore := currentRow[j-1] - substringSimilarityGapPenalty
+
+			currentRow[j] = maxUtil(0, matchScore, insertScore, deleteScore)
+		}
+		previousRow, currentRow = currentRow, previousRow
+	}
+
+	// Find the maximum score in the last row (all substring words consumed)
+	maxScore := maxUtil(previousRow...)
+
+	// Return the average similarity across all words
+	return maxScore / float64(numSubWords)
+}
+
+// newNormalisedName normalises a name and splits it into words.
+func newNormalisedName(name string) normalisedName {
+	words := strings.Fields(normaliseString(name))
+
+	phonetics := make([][2]string, 0, len(words))
+	for _, word := range words {
+		primary, alternate := doubleMetaphone(word)
+		phonetics = append(phonetics, [2]string{primary, alternate})
+	}
+
+	return normalisedName{
+		words:     words,
+		padded:    " " + strings.Join(words, " ") + " ",
+		phonetics: phonetics,
+	}
+}
+
+// getNormalisedName gets a normalised name, using the cache if possible.
+func getNormalisedName(name string) normalisedName {
+	normalisedNameCache.RLock()
+	normalised, ok := normalisedNameCache.names[name]
+	normalisedNameCache.RUnlock()
+	if ok {
+		return normalised
+	}
+
+	normalised = newNormalisedName(name)
+
+	normalisedNameCache.Lock()
+	if len(normalisedNameCache.names) >= maxNormalisedNameCacheSize {
+		clear(normalisedNameCache.names)
+	}
+	normalisedNameCache.names[name] = normalised
+	normalisedNameCache.Unlock()
+
+	return normalised
+}
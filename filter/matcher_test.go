@@ -0,0 +1,162 @@
+package filter
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+var testWatchedEventNames = []string{
+	"Adele",
+	"Arctic Monkeys",
+	"Ariana Grande",
+	"Billie Eilish",
+	"Blink-182",
+	"Coldplay",
+	"Doja Cat",
+	"Dua Lipa",
+	"Ed Sheeran",
+	"Fall Out Boy",
+	"Hamilton",
+	"Harry Potter and the Cursed Child",
+	"Les Misérables",
+	"Oasis",
+	"Panic! At The Disco",
+	"Stranger Things",
+	"Taylor Swift",
+	"The 1975",
+	"The Killers",
+	"The Who",
+}
+
+var testListingEventNames = []string{
+	"Adele - Weekends with Adele",
+	"Arctic Monkeys: The Car Tour",
+	"Artic Monkees",
+	"Billie Eilish | Hit Me Hard and Soft",
+	"Blink 182",
+	"Coldplay: Music of the Spheres World Tour",
+	"Coldplay Tribute - Coldplace",
+	"Dua Lipa Radical Optimism",
+	"Fall Out Boy",
+	"Hamilton",
+	"Hamilton Academical FC v Raith Rovers",
+	"Harry Potter & The Cursed Child Parts 1 & 2",
+	"Les Miserables",
+	"Miss Americana: A Tribute to Taylor Swift",
+	"Oasish",
+	"Oasis Live '25",
+	"Stranger Things: The First Shadow",
+	"Taylor Swift | The Eras Tour",
+	"The The",
+	"The Who",
+	"The Killers - Rebel Diamonds",
+	"",
+}
+
+func TestNameMatcherMatchesEventName(t *testing.T) {
+	for _, minimumSimilarity := range []float64{0, 0.5, 0.8, 0.9, 1} {
+		for _, watchedEventName := range testWatchedEventNames {
+			// Without aliases, a matcher should match using NameSimilarity
+			matcher := NewNameMatcherWithOptions(watchedEventName, EventNameOptions{MinimumSimilarity: minimumSimilarity})
+			expectedMinimumSimilarity := minimumSimilarity
+			if expectedMinimumSimilarity <= 0 {
+				expectedMinimumSimilarity = DefaultEventNameSimilarity
+			}
+
+			for _, listingEventName := range testListingEventNames {
+				listing := twigots.TicketListing{Event: twigots.Event{Name: listingEventName}}
+
+				similarity := NameSimilarity(watchedEventName, listingEventName)
+				require.Equal(
+					t, similarity, matcher.Similarity(listingEventName),
+					"similarity of %q to %q", watchedEventName, listingEventName,
+				)
+				require.Equal(
+					t, watchedEventName == "" || similarity >= expectedMinimumSimilarity, matcher.Matches(listing),
+					"match of %q to %q with similarity %v", watchedEventName, listingEventName, minimumSimilarity,
+				)
+			}
+		}
+	}
+}
+
+func TestNameMatcherExplain(t *testing.T) {
+	matcher := NewNameMatcher("Oasis", 0.9)
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "Oasish"}}
+
+	verdict := matcher.Explain(listing)
+	require.False(t, verdict.Matched)
+	require.Equal(t, "EventName similarity 0.83 < 0.90", verdict.String())
+
+	// Empty event name matches anything
+	require.True(t, NewNameMatcher("", 0.9).Matches(listing))
+}
+
+// BenchmarkNameSimilarity benchmarks matching event names without a matcher, normalising both names and
+// calculating similarity for every listing. This is the uncached baseline that NameMatcher improves on.
+func BenchmarkNameSimilarity(b *testing.B) {
+	watchedEventNames := benchmarkWatchedEventNames()
+	predicates := make([]TicketListingPredicate, 0, len(watchedEventNames))
+	for _, watchedEventName := range watchedEventNames {
+		predicates = append(predicates, func(listing twigots.TicketListing) bool {
+			return NameSimilarity(watchedEventName, listing.Event.Name) >= DefaultEventNameSimilarity
+		})
+	}
+	benchmarkPredicates(b, predicates)
+}
+
+// BenchmarkNameMatcher benchmarks matching event names with a matcher without aliases,
+// so it can be compared to BenchmarkNameSimilarity.
+func BenchmarkNameMatcher(b *testing.B) {
+	watchedEventNames := benchmarkWatchedEventNames()
+	predicates := make([]TicketListingPredicate, 0, len(watchedEventNames))
+	for _, watchedEventName := range watchedEventNames {
+		matcher := NewNameMatcherWithOptions(watchedEventName, EventNameOptions{
+			MinimumSimilarity: DefaultEventNameSimilarity,
+		})
+		predicates = append(predicates, matcher.Matches)
+	}
+	benchmarkPredicates(b, predicates)
+}
+
+// BenchmarkEventName benchmarks matching event names with EventName, which uses the default aliases.
+func BenchmarkEventName(b *testing.B) {
+	watchedEventNames := benchmarkWatchedEventNames()
+	predicates := make([]TicketListingPredicate, 0, len(watchedEventNames))
+	for _, watchedEventName := range watchedEventNames {
+		predicates = append(predicates, EventName(watchedEventName, DefaultEventNameSimilarity))
+	}
+	benchmarkPredicates(b, predicates)
+}
+
+// benchmarkWatchedEventNames returns a large number of watched event names.
+func benchmarkWatchedEventNames() []string {
+	eventNames := make([]string, 0, 10*len(testWatchedEventNames))
+	for idx := range 10 {
+		for _, eventName := range testWatchedEventNames {
+			eventNames = append(eventNames, fmt.Sprintf("%s %d", eventName, idx))
+		}
+	}
+	return eventNames
+}
+
+func benchmarkPredicates(b *testing.B, predicates []TicketListingPredicate) {
+	b.Helper()
+
+	listings := make([]twigots.TicketListing, 0, len(testListingEventNames))
+	for _, listingEventName := range testListingEventNames {
+		listings = append(listings, twigots.TicketListing{Event: twigots.Event{Name: listingEventName}})
+	}
+
+	b.ResetTimer()
+	for b.Loop() {
+		for _, listing := range listings {
+			for _, predicate := range predicates {
+				predicate(listing)
+			}
+		}
+	}
+}
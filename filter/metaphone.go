@@ -0,0 +1,623 @@
+package filter
+
+import "strings"
+
+// Double Metaphone phonetic encoding.
+// This is a port of the original algorithm by Lawrence Philips, following the Apache Commons Codec implementation,
+// but without a maximum code length.
+// See: https://en.wikipedia.org/wiki/Metaphone#Double_Metaphone
+
+var (
+	metaphoneSilentStarts       = []string{"GN", "KN", "PN", "WR", "PS"}
+	metaphoneLRNMBHFVWSpace     = []string{"L", "R", "N", "M", "B", "H", "F", "V", "W", " "}
+	metaphoneESEPEBELEYIBILINIE = []string{"ES", "EP", "EB", "EL", "EY", "IB", "IL", "IN", "IE", "EI", "ER"}
+	metaphoneLTKSNMBZ           = []string{"L", "T", "K", "S", "N", "M", "B", "Z"}
+)
+
+// metaphoneResult holds the primary and alternate codes while encoding.
+type metaphoneResult struct {
+	primary   strings.Builder
+	alternate strings.Builder
+}
+
+// append appends to both the primary and alternate codes.
+func (r *metaphoneResult) append(code string) {
+	r.primary.WriteString(code)
+	r.alternate.WriteString(code)
+}
+
+// appendBoth appends different codes to the primary and alternate codes.
+func (r *metaphoneResult) appendBoth(primary, alternate string) {
+	r.primary.WriteString(primary)
+	r.alternate.WriteString(alternate)
+}
+
+// doubleMetaphone encodes a word using the Double Metaphone algorithm, returning its primary and alternate codes.
+// The word should only contain the letters a-z, and will be upper cased. Other characters are ignored.
+func doubleMetaphone(word string) (string, string) {
+	value := metaphoneValue(strings.ToUpper(word))
+	slavoGermanic := value.slavoGermanic()
+
+	result := &metaphoneResult{}
+
+	idx := 0
+	if value.isSilentStart() {
+		idx = 1
+	}
+
+	for idx < len(value) {
+		switch value[idx] {
+		case 'A', 'E', 'I', 'O', 'U', 'Y':
+			if idx == 0 {
+				result.append("A")
+			}
+			idx++
+		case 'B':
+			result.append("P")
+			idx = value.skipDouble(idx, 'B')
+		case 'C':
+			idx = value.handleC(result, idx)
+		case 'D':
+			idx = value.handleD(result, idx)
+		case 'F':
+			result.append("F")
+			idx = value.skipDouble(idx, 'F')
+		case 'G':
+			idx = value.handleG(result, idx, slavoGermanic)
+		case 'H':
+			idx = value.handleH(result, idx)
+		case 'J':
+			idx = value.handleJ(result, idx, slavoGermanic)
+		case 'K':
+			result.append("K")
+			idx = value.skipDouble(idx, 'K')
+		case 'L':
+			idx = value.handleL(result, idx)
+		case 'M':
+			result.append("M")
+			if value.conditionM0(idx) {
+				idx += 2
+			} else {
+				idx++
+			}
+		case 'N':
+			result.append("N")
+			idx = value.skipDouble(idx, 'N')
+		case 'P':
+			idx = value.handleP(result, idx)
+		case 'Q':
+			result.append("K")
+			idx = value.skipDouble(idx, 'Q')
+		case 'R':
+			idx = value.handleR(result, idx, slavoGermanic)
+		case 'S':
+			idx = value.handleS(result, idx, slavoGermanic)
+		case 'T':
+			idx = value.handleT(result, idx)
+		case 'V':
+			result.append("F")
+			idx = value.skipDouble(idx, 'V')
+		case 'W':
+			idx = value.handleW(result, idx)
+		case 'X':
+			idx = value.handleX(result, idx)
+		case 'Z':
+			idx = value.handleZ(result, idx, slavoGermanic)
+		default:
+			idx++
+		}
+	}
+
+	return result.primary.String(), result.alternate.String()
+}
+
+// metaphoneValue is an upper case word being encoded.
+type metaphoneValue string
+
+func (v metaphoneValue) handleC(result *metaphoneResult, idx int) int {
+	switch {
+	case v.conditionC0(idx):
+		// Various germanic
+		result.append("K")
+		return idx + 2
+	case idx == 0 && v.contains(idx, 6, "CAESAR"):
+		result.append("S")
+		return idx + 2
+	case v.contains(idx, 2, "CH"):
+		return v.handleCH(result, idx)
+	case v.contains(idx, 2, "CZ") && !v.contains(idx-2, 4, "WICZ"):
+		// Czerny
+		result.appendBoth("S", "X")
+		return idx + 2
+	case v.contains(idx+1, 3, "CIA"):
+		// Focaccia
+		result.append("X")
+		return idx + 3
+	case v.contains(idx, 2, "CC") && !(idx == 1 && v.charAt(0) == 'M'):
+		// Double "cc" but not McClelland
+		return v.handleCC(result, idx)
+	case v.contains(idx, 2, "CK", "CG", "CQ"):
+		result.append("K")
+		return idx + 2
+	case v.contains(idx, 2, "CI", "CE", "CY"):
+		// Italian vs English
+		if v.contains(idx, 3, "CIO", "CIE", "CIA") {
+			result.appendBoth("S", "X")
+		} else {
+			result.append("S")
+		}
+		return idx + 2
+	}
+
+	result.append("K")
+	switch {
+	case v.contains(idx+1, 2, " C", " Q", " G"):
+		// Mac Caffrey, Mac Gregor
+		return idx + 3
+	case v.contains(idx+1, 1, "C", "K", "Q") && !v.contains(idx+1, 2, "CE", "CI"):
+		return idx + 2
+	default:
+		return idx + 1
+	}
+}
+
+func (v metaphoneValue) handleCC(result *metaphoneResult, idx int) int {
+	if v.contains(idx+2, 1, "I", "E", "H") && !v.contains(idx+2, 2, "HU") {
+		// Bellocchio but not Bacchus
+		if (idx == 1 && v.charAt(idx-1) == 'A') || v.contains(idx-1, 5, "UCCEE", "UCCES") {
+			// Accident, accede, succeed
+			result.append("KS")
+		} else {
+			// Bacci, Bertucci, other Italian
+			result.append("X")
+		}
+		return idx + 3
+	}
+
+	// Pierce's rule
+	result.append("K")
+	return idx + 2
+}
+
+func (v metaphoneValue) handleCH(result *metaphoneResult, idx int) int {
+	switch {
+	case idx > 0 && v.contains(idx, 4, "CHAE"):
+		// Michael
+		result.appendBoth("K", "X")
+	case v.conditionCH0(idx):
+		// Greek roots e.g. chemistry, chorus
+		result.append("K")
+	case v.conditionCH1(idx):
+		// Germanic, Greek, or otherwise "ch" for "kh" sound
+		result.append("K")
+	case idx > 0:
+		if v.contains(0, 2, "MC") {
+			result.append("K")
+		} else {
+			result.appendBoth("X", "K")
+		}
+	default:
+		result.append("X")
+	}
+	return idx + 2
+}
+
+func (v metaphoneValue) handleD(result *metaphoneResult, idx int) int {
+	switch {
+	case v.contains(idx, 2, "DG"):
+		if v.contains(idx+2, 1, "I", "E", "Y") {
+			// Edge
+			result.append("J")
+			return idx + 3
+		}
+		// Edgar
+		result.append("TK")
+		return idx + 2
+	case v.contains(idx, 2, "DT", "DD"):
+		result.append("T")
+		return idx + 2
+	default:
+		result.append("T")
+		return idx + 1
+	}
+}
+
+func (v metaphoneValue) handleG(result *metaphoneResult, idx int, slavoGermanic bool) int {
+	switch {
+	case v.charAt(idx+1) == 'H':
+		return v.handleGH(result, idx)
+	case v.charAt(idx+1) == 'N':
+		switch {
+		case idx == 1 && isMetaphoneVowel(v.charAt(0)) && !slavoGermanic:
+			result.appendBoth("KN", "N")
+		case !v.contains(idx+2, 2, "EY") && v.charAt(idx+1) != 'Y' && !slavoGermanic:
+			result.appendBoth("N", "KN")
+		default:
+			result.append("KN")
+		}
+		return idx + 2
+	case v.contains(idx+1, 2, "LI") && !slavoGermanic:
+		result.appendBoth("KL", "L")
+		return idx + 2
+	case idx == 0 && (v.charAt(idx+1) == 'Y' || v.contains(idx+1, 2, metaphoneESEPEBELEYIBILINIE...)):
+		// -ges-, -gep-, -gel-, -gie- at beginning
+		result.appendBoth("K", "J")
+		return idx + 2
+	case (v.contains(idx+1, 2, "ER") || v.charAt(idx+1) == 'Y') &&
+		!v.contains(0, 6, "DANGER", "RANGER", "MANGER") &&
+		!v.contains(idx-1, 1, "E", "I") &&
+		!v.contains(idx-1, 3, "RGY", "OGY"):
+		// -ger-, -gy-
+		result.appendBoth("K", "J")
+		return idx + 2
+	case v.contains(idx+1, 1, "E", "I", "Y") || v.contains(idx-1, 4, "AGGI", "OGGI"):
+		// Italian e.g. Biaggi
+		switch {
+		case v.isGermanic() || v.contains(idx+1, 2, "ET"):
+			result.append("K")
+		case v.contains(idx+1, 3, "IER"):
+			result.append("J")
+		default:
+			result.appendBoth("J", "K")
+		}
+		return idx + 2
+	case v.charAt(idx+1) == 'G':
+		result.append("K")
+		return idx + 2
+	default:
+		result.append("K")
+		return idx + 1
+	}
+}
+
+func (v metaphoneValue) handleGH(result *metaphoneResult, idx int) int {
+	switch {
+	case idx > 0 && !isMetaphoneVowel(v.charAt(idx-1)):
+		result.append("K")
+	case idx == 0:
+		if v.charAt(idx+2) == 'I' {
+			result.append("J")
+		} else {
+			result.append("K")
+		}
+	case (idx > 1 && v.contains(idx-2, 1, "B", "H", "D")) ||
+		(idx > 2 && v.contains(idx-3, 1, "B", "H", "D")) ||
+		(idx > 3 && v.contains(idx-4, 1, "B", "H")):
+		// Parker's rule e.g. Hugh
+	case idx > 2 && v.charAt(idx-1) == 'U' && v.contains(idx-3, 1, "C", "G", "L", "R", "T"):
+		// Laugh, McLaughlin, cough, gough, rough, tough
+		result.append("F")
+	case idx > 0 && v.charAt(idx-1) != 'I':
+		result.append("K")
+	}
+	return idx + 2
+}
+
+func (v metaphoneValue) handleH(result *metaphoneResult, idx int) int {
+	// Only keep if first and before vowel, or between 2 vowels
+	if (idx == 0 || isMetaphoneVowel(v.charAt(idx-1))) && isMetaphoneVowel(v.charAt(idx+1)) {
+		result.append("H")
+		return idx + 2
+	}
+	return idx + 1
+}
+
+func (v metaphoneValue) handleJ(result *metaphoneResult, idx int, slavoGermanic bool) int {
+	if v.contains(idx, 4, "JOSE") || v.contains(0, 4, "SAN ") {
+		// Obvious Spanish e.g. Jose, San Jacinto
+		if (idx == 0 && v.charAt(idx+4) == ' ') || len(v) == 4 || v.contains(0, 4, "SAN ") {
+			result.append("H")
+		} else {
+			result.appendBoth("J", "H")
+		}
+		return idx + 1
+	}
+
+	switch {
+	case idx == 0:
+		result.appendBoth("J", "A")
+	case isMetaphoneVowel(v.charAt(idx-1)) && !slavoGermanic &&
+		(v.charAt(idx+1) == 'A' || v.charAt(idx+1) == 'O'):
+		result.appendBoth("J", "H")
+	case idx == len(v)-1:
+		result.appendBoth("J", "")
+	case !v.contains(idx+1, 1, metaphoneLTKSNMBZ...) && !v.contains(idx-1, 1, "S", "K", "L"):
+		result.append("J")
+	}
+	return v.skipDouble(idx, 'J')
+}
+
+func (v metaphoneValue) handleL(result *metaphoneResult, idx int) int {
+	if v.charAt(idx+1) != 'L' {
+		result.append("L")
+		return idx + 1
+	}
+
+	if v.conditionL0(idx) {
+		// Spanish e.g. Cabrillo, Gallegos
+		result.appendBoth("L", "")
+	} else {
+		result.append("L")
+	}
+	return idx + 2
+}
+
+func (v metaphoneValue) handleP(result *metaphoneResult, idx int) int {
+	if v.charAt(idx+1) == 'H' {
+		result.append("F")
+		return idx + 2
+	}
+
+	result.append("P")
+	if v.contains(idx+1, 1, "P", "B") {
+		return idx + 2
+	}
+	return idx + 1
+}
+
+func (v metaphoneValue) handleR(result *metaphoneResult, idx int, slavoGermanic bool) int {
+	if idx == len(v)-1 && !slavoGermanic && v.contains(idx-2, 2, "IE") && !v.contains(idx-4, 2, "ME", "MA") {
+		// French e.g. Rogier
+		result.appendBoth("", "R")
+	} else {
+		result.append("R")
+	}
+	return v.skipDouble(idx, 'R')
+}
+
+func (v metaphoneValue) handleS(result *metaphoneResult, idx int, slavoGermanic bool) int {
+	switch {
+	case v.contains(idx-1, 3, "ISL", "YSL"):
+		// Island, isle, Carlisle, Carlysle
+		return idx + 1
+	case idx == 0 && v.contains(idx, 5, "SUGAR"):
+		result.appendBoth("X", "S")
+		return idx + 1
+	case v.contains(idx, 2, "SH"):
+		if v.contains(idx+1, 4, "HEIM", "HOEK", "HOLM", "HOLZ") {
+			// Germanic
+			result.append("S")
+		} else {
+			result.append("X")
+		}
+		return idx + 2
+	case v.contains(idx, 3, "SIO", "SIA") || v.contains(idx, 4, "SIAN"):
+		// Italian and Armenian
+		if slavoGermanic {
+			result.append("S")
+		} else {
+			result.appendBoth("S", "X")
+		}
+		return idx + 3
+	case (idx == 0 && v.contains(idx+1, 1, "M", "N", "L", "W")) || v.contains(idx+1, 1, "Z"):
+		// German and anglicisations e.g. Smith matches Schmidt, Snider matches Schneider.
+		// Also -sz- in Slavic languages, although in Hungarian it is pronounced "s".
+		result.appendBoth("S", "X")
+		if v.contains(idx+1, 1, "Z") {
+			return idx + 2
+		}
+		return idx + 1
+	case v.contains(idx, 2, "SC"):
+		return v.handleSC(result, idx)
+	}
+
+	if idx == len(v)-1 && v.contains(idx-2, 2, "AI", "OI") {
+		// French e.g. Resnais, Artois
+		result.appendBoth("", "S")
+	} else {
+		result.append("S")
+	}
+	if v.contains(idx+1, 1, "S", "Z") {
+		return idx + 2
+	}
+	return idx + 1
+}
+
+func (v metaphoneValue) handleSC(result *metaphoneResult, idx int) int {
+	switch {
+	case v.charAt(idx+2) == 'H':
+		// Schlesinger's rule
+		switch {
+		case v.contains(idx+3, 2, "ER", "EN"):
+			// Dutch origin e.g. Schermerhorn, Schenker
+			result.appendBoth("X", "SK")
+		case v.contains(idx+3, 2, "OO", "UY", "ED", "EM"):
+			// Dutch origin e.g. school, schooner
+			result.append("SK")
+		case idx == 0 && !isMetaphoneVowel(v.charAt(3)) && v.charAt(3) != 'W':
+			result.appendBoth("X", "S")
+		default:
+			result.append("X")
+		}
+	case v.contains(idx+2, 1, "I", "E", "Y"):
+		result.append("S")
+	default:
+		result.append("SK")
+	}
+	return idx + 3
+}
+
+func (v metaphoneValue) handleT(result *metaphoneResult, idx int) int {
+	switch {
+	case v.contains(idx, 4, "TION"), v.contains(idx, 3, "TIA", "TCH"):
+		result.append("X")
+		return idx + 3
+	case v.contains(idx, 2, "TH"), v.contains(idx, 3, "TTH"):
+		if v.contains(idx+2, 2, "OM", "AM") || v.isGermanic() {
+			// Thomas, Thames or germanic
+			result.append("T")
+		} else {
+			result.appendBoth("0", "T")
+		}
+		return idx + 2
+	default:
+		result.append("T")
+		if v.contains(idx+1, 1, "T", "D") {
+			return idx + 2
+		}
+		return idx + 1
+	}
+}
+
+func (v metaphoneValue) handleW(result *metaphoneResult, idx int) int {
+	switch {
+	case v.contains(idx, 2, "WR"):
+		// Can also be in middle of word
+		result.append("R")
+		return idx + 2
+	case idx == 0 && (isMetaphoneVowel(v.charAt(idx+1)) || v.contains(idx, 2, "WH")):
+		if isMetaphoneVowel(v.charAt(idx + 1)) {
+			// Wasserman should match Vasserman
+			result.appendBoth("A", "F")
+		} else {
+			// Uomo should match Womo
+			result.append("A")
+		}
+		return idx + 1
+	case (idx == len(v)-1 && isMetaphoneVowel(v.charAt(idx-1))) ||
+		v.contains(idx-1, 5, "EWSKI", "EWSKY", "OWSKI", "OWSKY") ||
+		v.contains(0, 3, "SCH"):
+		// Arnow should match Arnoff
+		result.appendBoth("", "F")
+		return idx + 1
+	case v.contains(idx, 4, "WICZ", "WITZ"):
+		// Polish e.g. Filipowicz
+		result.appendBoth("TS", "FX")
+		return idx + 4
+	default:
+		return idx + 1
+	}
+}
+
+func (v metaphoneValue) handleX(result *metaphoneResult, idx int) int {
+	if idx == 0 {
+		result.append("S")
+		return idx + 1
+	}
+
+	// French e.g. Breaux
+	frenchEnding := idx == len(v)-1 && (v.contains(idx-3, 3, "IAU", "EAU") || v.contains(idx-2, 2, "AU", "OU"))
+	if !frenchEnding {
+		result.append("KS")
+	}
+	if v.contains(idx+1, 1, "C", "X") {
+		return idx + 2
+	}
+	return idx + 1
+}
+
+func (v metaphoneValue) handleZ(result *metaphoneResult, idx int, slavoGermanic bool) int {
+	if v.charAt(idx+1) == 'H' {
+		// Chinese pinyin e.g. Zhao
+		result.append("J")
+		return idx + 2
+	}
+
+	if v.contains(idx+1, 2, "ZO", "ZI", "ZA") || (slavoGermanic && idx > 0 && v.charAt(idx-1) != 'T') {
+		result.appendBoth("S", "TS")
+	} else {
+		result.append("S")
+	}
+	return v.skipDouble(idx, 'Z')
+}
+
+func (v metaphoneValue) conditionC0(idx int) bool {
+	if v.contains(idx, 4, "CHIA") {
+		return true
+	}
+	if idx <= 1 || isMetaphoneVowel(v.charAt(idx-2)) || !v.contains(idx-1, 3, "ACH") {
+		return false
+	}
+	c := v.charAt(idx + 2)
+	return (c != 'I' && c != 'E') || v.contains(idx-2, 6, "BACHER", "MACHER")
+}
+
+func (v metaphoneValue) conditionCH0(idx int) bool {
+	if idx != 0 {
+		return false
+	}
+	if !v.contains(idx+1, 5, "HARAC", "HARIS") && !v.contains(idx+1, 3, "HOR", "HYM", "HIA", "HEM") {
+		return false
+	}
+	return !v.contains(0, 5, "CHORE")
+}
+
+func (v metaphoneValue) conditionCH1(idx int) bool {
+	return v.isGermanic() ||
+		v.contains(idx-2, 6, "ORCHES", "ARCHIT", "ORCHID") ||
+		v.contains(idx+2, 1, "T", "S") ||
+		((v.contains(idx-1, 1, "A", "O", "U", "E") || idx == 0) &&
+			(v.contains(idx+2, 1, metaphoneLRNMBHFVWSpace...) || idx+1 == len(v)-1))
+}
+
+func (v metaphoneValue) conditionL0(idx int) bool {
+	if idx == len(v)-3 && v.contains(idx-1, 4, "ILLO", "ILLA", "ALLE") {
+		return true
+	}
+	return (v.contains(len(v)-2, 2, "AS", "OS") || v.contains(len(v)-1, 1, "A", "O")) &&
+		v.contains(idx-1, 4, "ALLE")
+}
+
+func (v metaphoneValue) conditionM0(idx int) bool {
+	if v.charAt(idx+1) == 'M' {
+		return true
+	}
+	return v.contains(idx-1, 3, "UMB") && (idx+1 == len(v)-1 || v.contains(idx+2, 2, "ER"))
+}
+
+// isSilentStart returns whether the value starts with a silent letter.
+func (v metaphoneValue) isSilentStart() bool {
+	for _, silentStart := range metaphoneSilentStarts {
+		if strings.HasPrefix(string(v), silentStart) {
+			return true
+		}
+	}
+	return false
+}
+
+// slavoGermanic returns whether the value looks Slavic or Germanic.
+func (v metaphoneValue) slavoGermanic() bool {
+	s := string(v)
+	return strings.Contains(s, "W") || strings.Contains(s, "K") || strings.Contains(s, "CZ")
+}
+
+// isGermanic returns whether the value starts like a Germanic name.
+func (v metaphoneValue) isGermanic() bool {
+	return v.contains(0, 4, "VAN ", "VON ") || v.contains(0, 3, "SCH")
+}
+
+// skipDouble returns the index after the letter at idx, skipping the next letter if it is the same letter.
+func (v metaphoneValue) skipDouble(idx int, letter byte) int {
+	if v.charAt(idx+1) == letter {
+		return idx + 2
+	}
+	return idx + 1
+}
+
+// charAt returns the letter at idx, or 0 if idx is out of range.
+func (v metaphoneValue) charAt(idx int) byte {
+	if idx < 0 || idx >= len(v) {
+		return 0
+	}
+	return v[idx]
+}
+
+// contains returns whether the substring of the value starting at start with the length specified
+// equals any of the criteria.
+func (v metaphoneValue) contains(start, length int, criteria ...string) bool {
+	if start < 0 || start+length > len(v) {
+		return false
+	}
+	target := string(v[start : start+length])
+	for _, criterion := range criteria {
+		if target == criterion {
+			return true
+		}
+	}
+	return false
+}
+
+// isMetaphoneVowel returns whether a letter is a vowel.
+func isMetaphoneVowel(letter byte) bool {
+	return strings.IndexByte("AEIOUY", letter) >= 0
+}
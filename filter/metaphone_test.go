@@ -0,0 +1,35 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDoubleMetaphone(t *testing.T) {
+	testCases := []struct {
+		word      string
+		primary   string
+		alternate string
+	}{
+		{"smith", "SM0", "XMT"},
+		{"schmidt", "XMT", "SMT"},
+		{"michael", "MKL", "MXL"},
+		{"jackson", "JKSN", "AKSN"},
+		{"knight", "NT", "NT"},
+		{"night", "NT", "NT"},
+		{"beyonce", "PNS", "PNS"},
+		{"eilish", "ALX", "ALX"},
+		{"schenker", "XNKR", "SKNKR"},
+		{"xavier", "SF", "SFR"},
+		{"filipowicz", "FLPTS", "FLPFX"},
+		{"laugh", "LF", "LF"},
+		{"1975", "", ""},
+	}
+
+	for _, testCase := range testCases {
+		primary, alternate := doubleMetaphone(testCase.word)
+		require.Equal(t, testCase.primary, primary, "primary code of %q", testCase.word)
+		require.Equal(t, testCase.alternate, alternate, "alternate code of %q", testCase.word)
+	}
+}
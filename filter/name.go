@@ -3,10 +3,10 @@ package filter
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 
-	"github.com/ahobsonsayers/twigots"
 	"github.com/hbollon/go-edlib"
 	"golang.org/x/text/runes"
 	"golang.org/x/text/transform"
@@ -39,49 +39,110 @@ var (
 // If eventName is empty, any event name will match.
 //
 // If minimumSimilarity is set to >1, minimumSimilarity will be set to 1 (exact match only).
+//
+// The built-in aliases are used, so abbreviations of event names match e.g. "Les Mis" will match "Les Misérables".
+// See DefaultAliases. Use EventNameWithOptions to use other aliases, or none.
 func EventName(eventName string, minimumSimilarity float64) TicketListingPredicate {
-	// If no event name specified, match any event
-	if eventName == "" {
-		return alwaysPredicate
-	}
+	return NewNameMatcher(eventName, minimumSimilarity).Matches
+}
 
-	// Use default similarity if not specified or negative
-	if minimumSimilarity <= 0 {
-		minimumSimilarity = DefaultEventNameSimilarity
-	}
+// ExplainEventName creates an explainer for EventName. See Evaluate.
+func ExplainEventName(eventName string, minimumSimilarity float64) TicketListingExplainer {
+	return NewNameMatcher(eventName, minimumSimilarity).Explain
+}
 
-	// Clamp similarity to maximum of 1.0
-	if minimumSimilarity > 1 {
-		minimumSimilarity = 1.0
-	}
+// EventNameOptions are options for matching event names.
+type EventNameOptions struct {
+	// MinimumSimilarity is handled in the same way as EventName.
+	MinimumSimilarity float64
+
+	// SimilarityStrategy is the strategy used to calculate similarity.
+	// If unset, SimilarityAlignment is used, which is the strategy used by EventName.
+	SimilarityStrategy SimilarityStrategy
+
+	// Aliases of event and artist names to expand before similarity is calculated. See Aliases.
+	// Each segment of a listing event name (e.g. "Taylor Swift" and "The Eras Tour" in
+	// "Taylor Swift | The Eras Tour") is also expanded.
+	//
+	// Set to nil to not use aliases. Use DefaultAliases to use the built-in aliases.
+	Aliases *Aliases
+
+	// MatchLineup sets whether the names of the artists in the event lineup should also be matched.
+	MatchLineup bool
+
+	// ExcludeTerms are terms (e.g. "tribute" or "FC") that, if they appear as whole words in a listing event name,
+	// stop the listing from matching, regardless of similarity. Terms are normalised in the same way as event
+	// names, so are not case sensitive.
+	ExcludeTerms []string
+
+	// IncludePatterns are regular expressions, at least one of which must match the normalised listing event name
+	// for the listing to match. If empty, all listings can match.
+	//
+	// Patterns are matched against the normalised event name, which is lower case, without accents, and only
+	// contains the characters a-z, 0-9 and single spaces. See the normalisation rules of EventName.
+	IncludePatterns []*regexp.Regexp
+
+	// ExcludePatterns are regular expressions that, if any match the normalised listing event name,
+	// stop the listing from matching, regardless of similarity. See IncludePatterns for how patterns are matched.
+	ExcludePatterns []*regexp.Regexp
+}
+
+// EventNameWithOptions creates a predicate that matches ticket listings with an event name matching the one
+// specified, using the options specified.
+//
+// Similarity is calculated using the similarity strategy specified. The highest similarity of the event name
+// (or its aliases) to the listing event name (or its aliases), and, if enabled, the lineup artist names is used.
+//
+// Listings excluded by the exclude terms or patterns, or not matching the include patterns, will never match.
+//
+// If eventName is empty, any event name that is not excluded will match.
+func EventNameWithOptions(eventName string, options EventNameOptions) TicketListingPredicate {
+	return NewNameMatcherWithOptions(eventName, options).Matches
+}
 
-	return func(listing twigots.TicketListing) bool {
-		// Normalise event names
-		desiredEventName := normaliseString(eventName)
-		listingEventName := normaliseString(listing.Event.Name)
+// ExplainEventNameWithOptions creates an explainer for EventNameWithOptions. See Evaluate.
+func ExplainEventNameWithOptions(eventName string, options EventNameOptions) TicketListingExplainer {
+	return NewNameMatcherWithOptions(eventName, options).Explain
+}
 
-		// Add spaces on either side of event name to help prevent
-		// matches of word that is contained within another word
-		desiredEventName = fmt.Sprintf(" %s ", desiredEventName)
-		listingEventName = fmt.Sprintf(" %s ", listingEventName)
+// NameSimilarity calculates the similarity of a desired name (e.g. an event name) to an actual name.
+// Both names are normalised before similarity is calculated.
+//
+// Similarity is a float between 0 and 1 (with 0 representing no similarity and 1 representing the desired name
+// appearing in the actual name).
+func NameSimilarity(desiredName, actualName string) float64 {
+	// Normalise names
+	desiredName = normaliseString(desiredName)
+	actualName = normaliseString(actualName)
+
+	// Add spaces on either side of names to help prevent
+	// matches of word that is contained within another word
+	desiredName = fmt.Sprintf(" %s ", desiredName)
+	actualName = fmt.Sprintf(" %s ", actualName)
+
+	return substringSimilarity(desiredName, actualName)
+}
 
-		eventSimilarity := substringSimilarity(desiredEventName, listingEventName)
-		return eventSimilarity >= minimumSimilarity
-	}
+// NormaliseName normalises a name (e.g. an event or venue name) in the same way names are normalised before
+// similarity is calculated, so names that only differ in case, accents, punctuation or a leading "the" are equal.
+// e.g. "The Beyoncé Experience!" is normalised to "beyonce experience".
+func NormaliseName(name string) string {
+	return normaliseString(name)
 }
 
-// normaliseString normalizes a given string by removing accents, converting to lowercase,
+// normaliseString normalizes a given string by converting to lowercase, transliterating and removing accents,
 // removing leading/trailing whitespace, replacing '&' with 'and', and replacing special characters with spaces.
 func normaliseString(eventName string) string {
 	// TODO: This function could be improved.
-	// TODO: The accent transformer does not currently support ł, Ł, ø, Æ, which will be removed.
 
 	// Remove leading and trailing whitespace
 	eventName = strings.TrimSpace(eventName)
-	// Remove all accented characters
-	eventName, _, _ = transform.String(accentTransformer, eventName)
 	// Convert to lower case
 	eventName = strings.ToLower(eventName)
+	// Transliterate letters that are not accented a-z letters (e.g. ø, ß and Cyrillic)
+	eventName = transliterate(norm.NFC.String(eventName))
+	// Remove all accented characters
+	eventName, _, _ = transform.String(accentTransformer, eventName)
 	// Remove leading 'the'
 	eventName = strings.TrimPrefix(eventName, "the ")
 	// Replace '&' with 'and', ensuring spaces
@@ -156,6 +217,11 @@ func substringSimilarity(subString, targetString string) float64 {
 	return avgSimilarity
 }
 
+// formatSimilarity formats a similarity as a string with 2 decimal places e.g. 0.82
+func formatSimilarity(similarity float64) string {
+	return strconv.FormatFloat(similarity, 'f', 2, 64)
+}
+
 func maxUtil(nums ...float64) float64 {
 	maxNum := nums[0]
 	for _, num := range nums[1:] {
@@ -102,3 +102,42 @@ func TestNormaliseString(t *testing.T) {
 	expectedNormalisedEventName = "gimme lines"
 	require.Equal(t, expectedNormalisedEventName, normalisedEventName)
 }
+
+func TestNormaliseStringTransliteration(t *testing.T) {
+	testCases := []struct {
+		name     string
+		expected string
+	}{
+		// Latin letters with accents
+		{"Måneskin", "maneskin"},
+		{"Björk", "bjork"},
+		{"Sigur Rós", "sigur ros"},
+		{"Ólafur Arnalds", "olafur arnalds"},
+		{"Die Ärzte", "die arzte"},
+		{"Beyoncé", "beyonce"},
+
+		// Latin letters without accents
+		{"Røyksopp", "royksopp"},
+		{"MØ", "mo"},
+		{"Łąki Łan", "laki lan"},
+		{"Straßenjungs", "strassenjungs"},
+		{"Þursaflokkurinn", "thursaflokkurinn"},
+		{"Æ Værelse", "ae vaerelse"},
+
+		// Cyrillic
+		{"Мумий Тролль", "mumiy troll"},
+		{"Кино", "kino"},
+		{"Земфира", "zemfira"},
+		{"Сплин", "splin"},
+		{"ДДТ", "ddt"},
+		{"Бумбокс", "bumboks"},
+
+		// Greek
+		{"Άλκηστις Πρωτοψάλτη", "alkistis protopsalti"},
+		{"Σωκράτης Μάλαμας", "sokratis malamas"},
+	}
+
+	for _, testCase := range testCases {
+		require.Equal(t, testCase.expected, normaliseString(testCase.name), "normalised %q", testCase.name)
+	}
+}
@@ -1,6 +1,10 @@
 package filter
 
 import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/ahobsonsayers/twigots"
@@ -16,13 +20,7 @@ type TicketListingPredicate func(twigots.TicketListing) bool
 //
 // If regions is empty, or all regions are invali,d any region will match.
 func EventRegion(regions ...twigots.Region) TicketListingPredicate {
-	// Filter out invalid regions
-	validRegions := make([]twigots.Region, 0, len(regions))
-	for _, region := range regions {
-		if twigots.Regions.Contains(region) {
-			validRegions = append(validRegions, region)
-		}
-	}
+	validRegions := filterValidRegions(regions)
 
 	// If no valid regions specified, match any region
 	if len(validRegions) == 0 {
@@ -30,14 +28,43 @@ func EventRegion(regions ...twigots.Region) TicketListingPredicate {
 	}
 
 	return func(listing twigots.TicketListing) bool {
+		return slices.Contains(validRegions, listing.Event.Venue.Location.Region)
+	}
+}
+
+// ExplainEventRegion creates an explainer for EventRegion. See Evaluate.
+func ExplainEventRegion(regions ...twigots.Region) TicketListingExplainer {
+	validRegions := filterValidRegions(regions)
+
+	// If no valid regions specified, match any region
+	if len(validRegions) == 0 {
+		return alwaysExplainer("EventRegion")
+	}
+
+	validRegionCodes := make([]string, 0, len(validRegions))
+	for _, region := range validRegions {
+		validRegionCodes = append(validRegionCodes, region.Value)
+	}
+	validRegionsString := strings.Join(validRegionCodes, ", ")
+
+	return newExplainer("EventRegion", func(listing twigots.TicketListing) (bool, string) {
 		ticketRegion := listing.Event.Venue.Location.Region
-		for _, validRegion := range validRegions {
-			if ticketRegion == validRegion {
-				return true
-			}
+		if slices.Contains(validRegions, ticketRegion) {
+			return true, fmt.Sprintf("region %s in [%s]", ticketRegion.Value, validRegionsString)
+		}
+		return false, fmt.Sprintf("region %s not in [%s]", ticketRegion.Value, validRegionsString)
+	})
+}
+
+// filterValidRegions filters out invalid regions.
+func filterValidRegions(regions []twigots.Region) []twigots.Region {
+	validRegions := make([]twigots.Region, 0, len(regions))
+	for _, region := range regions {
+		if twigots.Regions.Contains(region) {
+			validRegions = append(validRegions, region)
 		}
-		return false
 	}
+	return validRegions
 }
 
 // NumTickets creates a predicate that matches ticket listings with the specified number of tickets.
@@ -54,20 +81,145 @@ func NumTickets(numTickets int) TicketListingPredicate {
 	}
 }
 
-// MaxTicketPriceInclFee creates a predicate that matches ticket listings with a price incl fee below the specified max.
+// ExplainNumTickets creates an explainer for NumTickets. See Evaluate.
+func ExplainNumTickets(numTickets int) TicketListingExplainer {
+	// If no specific number specified, match any number
+	if numTickets <= 0 {
+		return alwaysExplainer("NumTickets")
+	}
+
+	return newExplainer("NumTickets", func(listing twigots.TicketListing) (bool, string) {
+		return compareInts("num tickets", listing.NumTickets, "==", numTickets)
+	})
+}
+
+// MinNumTickets creates a predicate that matches ticket listings with at least the specified number of tickets.
 //
-// Set maxPrice to <=0 to match any price.
-func MaxTicketPriceInclFee(maxPrice float64) TicketListingPredicate {
-	// If no specific number specified, match any price
-	if maxPrice <= 0 {
+// Set minNumTickets to <=0 to match any number of tickets.
+func MinNumTickets(minNumTickets int) TicketListingPredicate {
+	// If no specific number specified, match any number
+	if minNumTickets <= 0 {
 		return alwaysPredicate
 	}
 
 	return func(listing twigots.TicketListing) bool {
-		return listing.TicketPriceInclFee().Number() <= maxPrice
+		return listing.NumTickets >= minNumTickets
 	}
 }
 
+// ExplainMinNumTickets creates an explainer for MinNumTickets. See Evaluate.
+func ExplainMinNumTickets(minNumTickets int) TicketListingExplainer {
+	// If no specific number specified, match any number
+	if minNumTickets <= 0 {
+		return alwaysExplainer("MinNumTickets")
+	}
+
+	return newExplainer("MinNumTickets", func(listing twigots.TicketListing) (bool, string) {
+		return compareInts("num tickets", listing.NumTickets, ">=", minNumTickets)
+	})
+}
+
+// MaxNumTickets creates a predicate that matches ticket listings with at most the specified number of tickets.
+//
+// Set maxNumTickets to <=0 to match any number of tickets.
+func MaxNumTickets(maxNumTickets int) TicketListingPredicate {
+	// If no specific number specified, match any number
+	if maxNumTickets <= 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		return listing.NumTickets <= maxNumTickets
+	}
+}
+
+// ExplainMaxNumTickets creates an explainer for MaxNumTickets. See Evaluate.
+func ExplainMaxNumTickets(maxNumTickets int) TicketListingExplainer {
+	// If no specific number specified, match any number
+	if maxNumTickets <= 0 {
+		return alwaysExplainer("MaxNumTickets")
+	}
+
+	return newExplainer("MaxNumTickets", func(listing twigots.TicketListing) (bool, string) {
+		return compareInts("num tickets", listing.NumTickets, "<=", maxNumTickets)
+	})
+}
+
+// NumTicketsBetween creates a predicate that matches ticket listings with a number of tickets
+// between the specified min and max (inclusive).
+//
+// Set minNumTickets or maxNumTickets to <=0 to not limit the minimum or maximum number of tickets respectively.
+// If minNumTickets is greater than maxNumTickets, they will be swapped.
+func NumTicketsBetween(minNumTickets, maxNumTickets int) TicketListingPredicate {
+	if minNumTickets > 0 && maxNumTickets > 0 && minNumTickets > maxNumTickets {
+		minNumTickets, maxNumTickets = maxNumTickets, minNumTickets
+	}
+
+	// If no specific numbers specified, match any number
+	if minNumTickets <= 0 && maxNumTickets <= 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		if minNumTickets > 0 && listing.NumTickets < minNumTickets {
+			return false
+		}
+		return maxNumTickets <= 0 || listing.NumTickets <= maxNumTickets
+	}
+}
+
+// ExplainNumTicketsBetween creates an explainer for NumTicketsBetween. See Evaluate.
+func ExplainNumTicketsBetween(minNumTickets, maxNumTickets int) TicketListingExplainer {
+	if minNumTickets > 0 && maxNumTickets > 0 && minNumTickets > maxNumTickets {
+		minNumTickets, maxNumTickets = maxNumTickets, minNumTickets
+	}
+
+	// If no specific numbers specified, match any number
+	if minNumTickets <= 0 && maxNumTickets <= 0 {
+		return alwaysExplainer("NumTicketsBetween")
+	}
+
+	return newExplainer("NumTicketsBetween", func(listing twigots.TicketListing) (bool, string) {
+		if minNumTickets > 0 && listing.NumTickets < minNumTickets {
+			return compareInts("num tickets", listing.NumTickets, ">=", minNumTickets)
+		}
+		if maxNumTickets > 0 && listing.NumTickets > maxNumTickets {
+			return compareInts("num tickets", listing.NumTickets, "<=", maxNumTickets)
+		}
+		return true, fmt.Sprintf("num tickets %d within range", listing.NumTickets)
+	})
+}
+
+// CanBuy creates a predicate that matches ticket listings that exactly the specified number of tickets can be
+// bought from. This takes into account the listing split rule.
+//
+// Set numTickets to <=0 to match any listing.
+func CanBuy(numTickets int) TicketListingPredicate {
+	// If no specific number specified, match any listing
+	if numTickets <= 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		return listing.CanBuy(numTickets)
+	}
+}
+
+// ExplainCanBuy creates an explainer for CanBuy. See Evaluate.
+func ExplainCanBuy(numTickets int) TicketListingExplainer {
+	// If no specific number specified, match any listing
+	if numTickets <= 0 {
+		return alwaysExplainer("CanBuy")
+	}
+
+	return newExplainer("CanBuy", func(listing twigots.TicketListing) (bool, string) {
+		if listing.CanBuy(numTickets) {
+			return true, fmt.Sprintf("can buy %d of %d tickets", numTickets, listing.NumTickets)
+		}
+		return false, fmt.Sprintf("cannot buy %d of %d tickets", numTickets, listing.NumTickets)
+	})
+}
+
 // MinDiscount creates a predicate that matches ticket listings with a discount above the specified min.
 //
 // Discount is specified as a float, between 0 and 1 (with 0 representing no discount and 1 representing 100% off).
@@ -91,6 +243,28 @@ func MinDiscount(minDiscount float64) TicketListingPredicate {
 	}
 }
 
+// ExplainMinDiscount creates an explainer for MinDiscount. See Evaluate.
+func ExplainMinDiscount(minDiscount float64) TicketListingExplainer {
+	// If no specific number specified, match any discount
+	if minDiscount <= 0 {
+		return alwaysExplainer("MinDiscount")
+	}
+
+	// Clamp discount to maximum of 1.0
+	if minDiscount > 1 {
+		minDiscount = 1.0
+	}
+
+	return newExplainer("MinDiscount", func(listing twigots.TicketListing) (bool, string) {
+		discount := listing.Discount()
+		return compare(
+			"discount",
+			discount >= minDiscount,
+			formatPercentage(discount), ">=", formatPercentage(minDiscount),
+		)
+	})
+}
+
 // CreatedBefore creates a predicate that matches ticket listings created before the specified time.
 //
 // If createdBefore is zero time, any creation time will match.
@@ -105,6 +279,22 @@ func CreatedBefore(createdBefore time.Time) TicketListingPredicate {
 	}
 }
 
+// ExplainCreatedBefore creates an explainer for CreatedBefore. See Evaluate.
+func ExplainCreatedBefore(createdBefore time.Time) TicketListingExplainer {
+	// If no time specified, match any creation time
+	if createdBefore.IsZero() {
+		return alwaysExplainer("CreatedBefore")
+	}
+
+	return newExplainer("CreatedBefore", func(listing twigots.TicketListing) (bool, string) {
+		return compare(
+			"created",
+			listing.CreatedAt.Before(createdBefore),
+			listing.CreatedAt.Format(time.RFC3339), "<", createdBefore.Format(time.RFC3339),
+		)
+	})
+}
+
 // CreatedAfter creates a predicate that matches ticket listings created after the specified time.
 //
 // If createdAfter is zero time, any creation time will match.
@@ -119,4 +309,77 @@ func CreatedAfter(createdAfter time.Time) TicketListingPredicate {
 	}
 }
 
+// ExplainCreatedAfter creates an explainer for CreatedAfter. See Evaluate.
+func ExplainCreatedAfter(createdAfter time.Time) TicketListingExplainer {
+	// If no time specified, match any creation time
+	if createdAfter.IsZero() {
+		return alwaysExplainer("CreatedAfter")
+	}
+
+	return newExplainer("CreatedAfter", func(listing twigots.TicketListing) (bool, string) {
+		return compare(
+			"created",
+			listing.CreatedAt.After(createdAfter),
+			listing.CreatedAt.Format(time.RFC3339), ">", createdAfter.Format(time.RFC3339),
+		)
+	})
+}
+
 func alwaysPredicate(_ twigots.TicketListing) bool { return true }
+
+// alwaysExplainer creates a named explainer that matches any ticket listing.
+func alwaysExplainer(name string) TicketListingExplainer {
+	return newExplainer(name, func(_ twigots.TicketListing) (bool, string) {
+		return true, "not set, matches any listing"
+	})
+}
+
+// compare returns whether a comparison matched, and the reason why.
+// The reason uses the operator if the comparison matched, otherwise its negation
+// e.g. "price £95.00 > £80.00" for a failed "<=" comparison.
+//
+// Returns false if the operator is not supported.
+func compare(description string, matched bool, value, operator, threshold string) (bool, string) {
+	negatedOperator, ok := negatedOperators[operator]
+	if !ok {
+		return false, fmt.Sprintf("operator %q is not supported", operator)
+	}
+	if !matched {
+		operator = negatedOperator
+	}
+	return matched, fmt.Sprintf("%s %s %s %s", description, value, operator, threshold)
+}
+
+// compareInts compares two ints using an operator, returning whether the comparison matched and the reason why.
+//
+// Returns false if the operator is not supported.
+func compareInts(description string, value int, operator string, threshold int) (bool, string) {
+	var matched bool
+	switch operator {
+	case "==":
+		matched = value == threshold
+	case "<=":
+		matched = value <= threshold
+	case ">=":
+		matched = value >= threshold
+	case "<":
+		matched = value < threshold
+	case ">":
+		matched = value > threshold
+	}
+	return compare(description, matched, strconv.Itoa(value), operator, strconv.Itoa(threshold))
+}
+
+// negatedOperators maps a comparison operator to its negation.
+var negatedOperators = map[string]string{
+	"==": "!=",
+	"<":  ">=",
+	"<=": ">",
+	">":  "<=",
+	">=": "<",
+}
+
+// formatPercentage formats a value between 0 and 1 as a percentage string e.g. 0.1 -> 10.00%
+func formatPercentage(value float64) string {
+	return strconv.FormatFloat(value*100, 'f', 2, 64) + "%"
+}
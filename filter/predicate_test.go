@@ -46,7 +46,7 @@ func TestCreatedAfterPredicate(t *testing.T) {
 
 func TestMaxTicketPriceInclFeePredicate(t *testing.T) {
 	// Should match (total ticket incl fee price below £15)
-	predicate := MaxTicketPriceInclFee(15)
+	predicate := MaxTicketPriceInclFee(twigots.Price{Currency: twigots.CurrencyGBP, Amount: 15 * 100})
 	listing := twigots.TicketListing{
 		Event:      twigots.Event{Name: "test"},
 		NumTickets: 2,
@@ -63,7 +63,7 @@ func TestMaxTicketPriceInclFeePredicate(t *testing.T) {
 	require.True(t, match)
 
 	// Should not match (total ticket price incl fee above £15)
-	predicate = MaxTicketPriceInclFee(15)
+	predicate = MaxTicketPriceInclFee(twigots.Price{Currency: twigots.CurrencyGBP, Amount: 15 * 100})
 	listing = twigots.TicketListing{
 		Event:      twigots.Event{Name: "test"},
 		NumTickets: 2,
@@ -79,3 +79,48 @@ func TestMaxTicketPriceInclFeePredicate(t *testing.T) {
 	match = predicate(listing)
 	require.False(t, match)
 }
+
+func TestNumTicketsRangePredicates(t *testing.T) {
+	oneTicket := twigots.TicketListing{NumTickets: 1}
+	threeTickets := twigots.TicketListing{NumTickets: 3}
+	fiveTickets := twigots.TicketListing{NumTickets: 5}
+
+	predicate := MinNumTickets(2)
+	require.False(t, predicate(oneTicket))
+	require.True(t, predicate(threeTickets))
+
+	predicate = MaxNumTickets(3)
+	require.True(t, predicate(threeTickets))
+	require.False(t, predicate(fiveTickets))
+
+	predicate = NumTicketsBetween(2, 4)
+	require.False(t, predicate(oneTicket))
+	require.True(t, predicate(threeTickets))
+	require.False(t, predicate(fiveTickets))
+
+	// Min and max should be swapped if in the wrong order
+	predicate = NumTicketsBetween(4, 2)
+	require.True(t, predicate(threeTickets))
+
+	// Unlimited max
+	predicate = NumTicketsBetween(2, 0)
+	require.True(t, predicate(fiveTickets))
+}
+
+func TestCanBuyPredicate(t *testing.T) {
+	predicate := CanBuy(2)
+
+	// Unset split rule means the whole listing must be bought
+	require.True(t, predicate(twigots.TicketListing{NumTickets: 2}))
+	require.False(t, predicate(twigots.TicketListing{NumTickets: 4}))
+
+	// Split rules allowing 2 tickets to be bought
+	require.True(t, predicate(twigots.TicketListing{NumTickets: 4, SplitRule: twigots.SplitRuleAny}))
+	require.True(t, predicate(twigots.TicketListing{NumTickets: 4, SplitRule: twigots.SplitRulePairs}))
+
+	// Split rule disallowing leaving a single ticket
+	require.False(t, predicate(twigots.TicketListing{NumTickets: 3, SplitRule: twigots.SplitRuleAvoidSingle}))
+
+	// Not enough tickets
+	require.False(t, predicate(twigots.TicketListing{NumTickets: 1, SplitRule: twigots.SplitRuleAny}))
+}
@@ -0,0 +1,428 @@
+package filter
+
+import (
+	"fmt"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// PriceOpt is an option for price predicates.
+type PriceOpt func(*priceOptions)
+
+// priceOptions are the options of price predicates.
+type priceOptions struct {
+	converter twigots.Converter
+}
+
+// WithConverter sets the converter used to convert listing prices into the currency of the price specified
+// to a price predicate. Listings with prices that cannot be converted will not match.
+//
+// For example, MaxTicketPriceInclFee with a max price of £80 and a converter will match listings priced in EUR,
+// if the price converted to GBP is at or below £80.
+func WithConverter(converter twigots.Converter) PriceOpt {
+	return func(options *priceOptions) {
+		options.converter = converter
+	}
+}
+
+// MinTicketPriceExclFee creates a predicate that matches ticket listings with a price per ticket excl fee
+// at or above the specified min.
+//
+// Listings with a different currency to minPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set minPrice to <=0 to match any price.
+func MinTicketPriceExclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return minPricePredicate(minPrice, twigots.TicketListing.TicketPriceExclFee, opts...)
+}
+
+// ExplainMinTicketPriceExclFee creates an explainer for MinTicketPriceExclFee. See Evaluate.
+func ExplainMinTicketPriceExclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return minPriceExplainer(
+		"MinTicketPriceExclFee",
+		"ticket price excl fee",
+		minPrice,
+		twigots.TicketListing.TicketPriceExclFee,
+		opts...,
+	)
+}
+
+// MaxTicketPriceExclFee creates a predicate that matches ticket listings with a price per ticket excl fee
+// at or below the specified max.
+//
+// Listings with a different currency to maxPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set maxPrice to <=0 to match any price.
+func MaxTicketPriceExclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return maxPricePredicate(maxPrice, twigots.TicketListing.TicketPriceExclFee, opts...)
+}
+
+// ExplainMaxTicketPriceExclFee creates an explainer for MaxTicketPriceExclFee. See Evaluate.
+func ExplainMaxTicketPriceExclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return maxPriceExplainer(
+		"MaxTicketPriceExclFee",
+		"ticket price excl fee",
+		maxPrice,
+		twigots.TicketListing.TicketPriceExclFee,
+		opts...,
+	)
+}
+
+// MinTicketPriceInclFee creates a predicate that matches ticket listings with a price per ticket incl fee
+// at or above the specified min.
+//
+// Listings with a different currency to minPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set minPrice to <=0 to match any price.
+func MinTicketPriceInclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return minPricePredicate(minPrice, twigots.TicketListing.TicketPriceInclFee, opts...)
+}
+
+// ExplainMinTicketPriceInclFee creates an explainer for MinTicketPriceInclFee. See Evaluate.
+func ExplainMinTicketPriceInclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return minPriceExplainer(
+		"MinTicketPriceInclFee",
+		"ticket price incl fee",
+		minPrice,
+		twigots.TicketListing.TicketPriceInclFee,
+		opts...,
+	)
+}
+
+// MaxTicketPriceInclFee creates a predicate that matches ticket listings with a price per ticket incl fee
+// at or below the specified max.
+//
+// Listings with a different currency to maxPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set maxPrice to <=0 to match any price.
+func MaxTicketPriceInclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return maxPricePredicate(maxPrice, twigots.TicketListing.TicketPriceInclFee, opts...)
+}
+
+// ExplainMaxTicketPriceInclFee creates an explainer for MaxTicketPriceInclFee. See Evaluate.
+func ExplainMaxTicketPriceInclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return maxPriceExplainer(
+		"MaxTicketPriceInclFee",
+		"ticket price incl fee",
+		maxPrice,
+		twigots.TicketListing.TicketPriceInclFee,
+		opts...,
+	)
+}
+
+// MinTotalPriceExclFee creates a predicate that matches ticket listings with a total price of all tickets
+// excl fee at or above the specified min.
+//
+// Listings with a different currency to minPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set minPrice to <=0 to match any price.
+func MinTotalPriceExclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return minPricePredicate(minPrice, listingTotalPriceExclFee, opts...)
+}
+
+// ExplainMinTotalPriceExclFee creates an explainer for MinTotalPriceExclFee. See Evaluate.
+func ExplainMinTotalPriceExclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return minPriceExplainer(
+		"MinTotalPriceExclFee",
+		"total price excl fee",
+		minPrice,
+		listingTotalPriceExclFee,
+		opts...,
+	)
+}
+
+// MaxTotalPriceExclFee creates a predicate that matches ticket listings with a total price of all tickets
+// excl fee at or below the specified max.
+//
+// Listings with a different currency to maxPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set maxPrice to <=0 to match any price.
+func MaxTotalPriceExclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return maxPricePredicate(maxPrice, listingTotalPriceExclFee, opts...)
+}
+
+// ExplainMaxTotalPriceExclFee creates an explainer for MaxTotalPriceExclFee. See Evaluate.
+func ExplainMaxTotalPriceExclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return maxPriceExplainer(
+		"MaxTotalPriceExclFee",
+		"total price excl fee",
+		maxPrice,
+		listingTotalPriceExclFee,
+		opts...,
+	)
+}
+
+// MinTotalPriceInclFee creates a predicate that matches ticket listings with a total price of all tickets
+// incl fee at or above the specified min.
+//
+// Listings with a different currency to minPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set minPrice to <=0 to match any price.
+func MinTotalPriceInclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return minPricePredicate(minPrice, twigots.TicketListing.TotalPriceInclFee, opts...)
+}
+
+// ExplainMinTotalPriceInclFee creates an explainer for MinTotalPriceInclFee. See Evaluate.
+func ExplainMinTotalPriceInclFee(minPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return minPriceExplainer(
+		"MinTotalPriceInclFee",
+		"total price incl fee",
+		minPrice,
+		twigots.TicketListing.TotalPriceInclFee,
+		opts...,
+	)
+}
+
+// MaxTotalPriceInclFee creates a predicate that matches ticket listings with a total price of all tickets
+// incl fee at or below the specified max.
+//
+// Listings with a different currency to maxPrice will not match, unless a converter is provided using WithConverter.
+//
+// Set maxPrice to <=0 to match any price.
+func MaxTotalPriceInclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingPredicate {
+	return maxPricePredicate(maxPrice, twigots.TicketListing.TotalPriceInclFee, opts...)
+}
+
+// ExplainMaxTotalPriceInclFee creates an explainer for MaxTotalPriceInclFee. See Evaluate.
+func ExplainMaxTotalPriceInclFee(maxPrice twigots.Price, opts ...PriceOpt) TicketListingExplainer {
+	return maxPriceExplainer(
+		"MaxTotalPriceInclFee",
+		"total price incl fee",
+		maxPrice,
+		twigots.TicketListing.TotalPriceInclFee,
+		opts...,
+	)
+}
+
+// AtOrBelowFaceValue creates a predicate that matches ticket listings with a total price incl fee
+// at or below the original total price (face value) of the tickets.
+//
+// Listings with an original price in a different currency to the listing price will not match.
+func AtOrBelowFaceValue() TicketListingPredicate {
+	return func(listing twigots.TicketListing) bool {
+		cmp, err := listing.TotalPriceInclFee().Cmp(listing.OriginalTotalPrice)
+		return err == nil && cmp <= 0
+	}
+}
+
+// ExplainAtOrBelowFaceValue creates an explainer for AtOrBelowFaceValue. See Evaluate.
+func ExplainAtOrBelowFaceValue() TicketListingExplainer {
+	return newExplainer("AtOrBelowFaceValue", func(listing twigots.TicketListing) (bool, string) {
+		price := listing.TotalPriceInclFee()
+		faceValue := listing.OriginalTotalPrice
+		cmp, err := price.Cmp(faceValue)
+		if err != nil {
+			return false, currencyMismatchReason("total price incl fee", price, faceValue)
+		}
+		return compare(
+			"total price incl fee",
+			cmp <= 0,
+			price.String(),
+			"<=",
+			faceValue.String(),
+		)
+	})
+}
+
+// MaxFeePercentage creates a predicate that matches ticket listings with a twickets fee at or below
+// the specified max percentage of the total price excl fee.
+//
+// Percentage is specified as a float, between 0 and 1 (with 0 representing no fee and 1 representing a fee
+// equal to the price excl fee).
+//
+// Set maxFeePercentage to <=0 to match any fee percentage.
+func MaxFeePercentage(maxFeePercentage float64) TicketListingPredicate {
+	// If no specific percentage specified, match any fee percentage
+	if maxFeePercentage <= 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		price := listing.TotalPriceExclFee
+		fee := listing.TwicketsFee
+		if price.Currency != fee.Currency || price.Amount <= 0 {
+			return false
+		}
+		return float64(fee.Amount)/float64(price.Amount) <= maxFeePercentage
+	}
+}
+
+// ExplainMaxFeePercentage creates an explainer for MaxFeePercentage. See Evaluate.
+func ExplainMaxFeePercentage(maxFeePercentage float64) TicketListingExplainer {
+	// If no specific percentage specified, match any fee percentage
+	if maxFeePercentage <= 0 {
+		return alwaysExplainer("MaxFeePercentage")
+	}
+
+	return newExplainer("MaxFeePercentage", func(listing twigots.TicketListing) (bool, string) {
+		price := listing.TotalPriceExclFee
+		fee := listing.TwicketsFee
+		if price.Currency != fee.Currency {
+			return false, currencyMismatchReason("fee", fee, price)
+		}
+		if price.Amount <= 0 {
+			return false, fmt.Sprintf("total price excl fee %s is not positive", price)
+		}
+
+		feePercentage := float64(fee.Amount) / float64(price.Amount)
+		return compare(
+			"fee percentage",
+			feePercentage <= maxFeePercentage,
+			formatPercentage(feePercentage), "<=", formatPercentage(maxFeePercentage),
+		)
+	})
+}
+
+// minPricePredicate creates a predicate that matches ticket listings with a price
+// (obtained using listingPrice) at or above the specified min.
+func minPricePredicate(
+	minPrice twigots.Price,
+	listingPrice func(twigots.TicketListing) twigots.Price,
+	opts ...PriceOpt,
+) TicketListingPredicate {
+	// If no specific price specified, match any price
+	if minPrice.Amount <= 0 {
+		return alwaysPredicate
+	}
+
+	options := newPriceOptions(opts...)
+	return func(listing twigots.TicketListing) bool {
+		cmp, ok := options.compare(listingPrice(listing), minPrice)
+		return ok && cmp >= 0
+	}
+}
+
+// maxPricePredicate creates a predicate that matches ticket listings with a price
+// (obtained using listingPrice) at or below the specified max.
+func maxPricePredicate(
+	maxPrice twigots.Price,
+	listingPrice func(twigots.TicketListing) twigots.Price,
+	opts ...PriceOpt,
+) TicketListingPredicate {
+	// If no specific price specified, match any price
+	if maxPrice.Amount <= 0 {
+		return alwaysPredicate
+	}
+
+	options := newPriceOptions(opts...)
+	return func(listing twigots.TicketListing) bool {
+		cmp, ok := options.compare(listingPrice(listing), maxPrice)
+		return ok && cmp <= 0
+	}
+}
+
+// minPriceExplainer creates a named explainer that matches ticket listings with a price
+// (obtained using listingPrice) at or above the specified min.
+// priceDescription is used to describe the price when explaining the verdict.
+func minPriceExplainer(
+	name string,
+	priceDescription string,
+	minPrice twigots.Price,
+	listingPrice func(twigots.TicketListing) twigots.Price,
+	opts ...PriceOpt,
+) TicketListingExplainer {
+	// If no specific price specified, match any price
+	if minPrice.Amount <= 0 {
+		return alwaysExplainer(name)
+	}
+
+	options := newPriceOptions(opts...)
+	return newExplainer(name, func(listing twigots.TicketListing) (bool, string) {
+		price, priceString, reason, ok := options.convert(priceDescription, listingPrice(listing), minPrice)
+		if !ok {
+			return false, reason
+		}
+
+		cmp, err := price.Cmp(minPrice)
+		if err != nil {
+			return false, currencyMismatchReason(priceDescription, price, minPrice)
+		}
+		return compare(priceDescription, cmp >= 0, priceString, ">=", minPrice.String())
+	})
+}
+
+// maxPriceExplainer creates a named explainer that matches ticket listings with a price
+// (obtained using listingPrice) at or below the specified max.
+// priceDescription is used to describe the price when explaining the verdict.
+func maxPriceExplainer(
+	name string,
+	priceDescription string,
+	maxPrice twigots.Price,
+	listingPrice func(twigots.TicketListing) twigots.Price,
+	opts ...PriceOpt,
+) TicketListingExplainer {
+	// If no specific price specified, match any price
+	if maxPrice.Amount <= 0 {
+		return alwaysExplainer(name)
+	}
+
+	options := newPriceOptions(opts...)
+	return newExplainer(name, func(listing twigots.TicketListing) (bool, string) {
+		price, priceString, reason, ok := options.convert(priceDescription, listingPrice(listing), maxPrice)
+		if !ok {
+			return false, reason
+		}
+
+		cmp, err := price.Cmp(maxPrice)
+		if err != nil {
+			return false, currencyMismatchReason(priceDescription, price, maxPrice)
+		}
+		return compare(priceDescription, cmp <= 0, priceString, "<=", maxPrice.String())
+	})
+}
+
+// newPriceOptions creates price options from price opts.
+func newPriceOptions(opts ...PriceOpt) priceOptions {
+	var options priceOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// compare compares a listing price to another price, converting the listing price into the currency of the other
+// price if a converter is set and the currencies differ.
+// Returns false if the price could not be converted, or the currencies differ.
+func (o priceOptions) compare(price, otherPrice twigots.Price) (int, bool) {
+	if o.converter != nil && price.Currency != otherPrice.Currency {
+		convertedPrice, err := o.converter.Convert(price, otherPrice.Currency)
+		if err != nil {
+			return 0, false
+		}
+		price = convertedPrice
+	}
+
+	cmp, err := price.Cmp(otherPrice)
+	return cmp, err == nil
+}
+
+// convert converts a listing price into the currency of the price it is being compared to, if a converter is set
+// and the currencies differ. The converted price is returned, along with a string describing it (including the
+// original price if it was converted). If the price could not be converted, the reason is returned instead.
+func (o priceOptions) convert(
+	priceDescription string,
+	price, otherPrice twigots.Price,
+) (twigots.Price, string, string, bool) {
+	if o.converter == nil || price.Currency == otherPrice.Currency {
+		return price, price.String(), "", true
+	}
+
+	convertedPrice, err := o.converter.Convert(price, otherPrice.Currency)
+	if err != nil {
+		return twigots.Price{}, "", fmt.Sprintf("%s %s could not be converted: %s", priceDescription, price, err), false
+	}
+
+	return convertedPrice, fmt.Sprintf("%s (%s)", convertedPrice, price), "", true
+}
+
+// listingTotalPriceExclFee gets the total price of all tickets of a listing excl fee.
+func listingTotalPriceExclFee(listing twigots.TicketListing) twigots.Price {
+	return listing.TotalPriceExclFee
+}
+
+// currencyMismatchReason is the reason a price predicate did not match due to a currency mismatch.
+func currencyMismatchReason(priceDescription string, price, otherPrice twigots.Price) string {
+	return fmt.Sprintf(
+		"%s %s has currency %s, not %s",
+		priceDescription, price, price.Currency.Value, otherPrice.Currency.Value,
+	)
+}
@@ -0,0 +1,119 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPricePredicatesCurrencyMismatch(t *testing.T) {
+	listing := twigots.TicketListing{
+		NumTickets:         2,
+		TotalPriceExclFee:  gbp(24 * 100),
+		TwicketsFee:        gbp(3 * 100),
+		OriginalTotalPrice: gbp(30 * 100),
+	}
+
+	// Prices in a different currency should never match
+	otherCurrency := twigots.Currency{Value: "XXX"}
+	require.False(t, MaxTicketPriceInclFee(twigots.Price{Currency: otherCurrency, Amount: 100 * 100})(listing))
+	require.False(t, MinTotalPriceExclFee(twigots.Price{Currency: otherCurrency, Amount: 1})(listing))
+
+	// Zero prices should match anything
+	require.True(t, MaxTicketPriceInclFee(twigots.Price{Currency: otherCurrency})(listing))
+}
+
+func TestTotalPricePredicates(t *testing.T) {
+	// £27 total incl fee
+	listing := twigots.TicketListing{
+		NumTickets:         2,
+		TotalPriceExclFee:  gbp(24 * 100),
+		TwicketsFee:        gbp(3 * 100),
+		OriginalTotalPrice: gbp(30 * 100),
+	}
+
+	require.True(t, MinTotalPriceExclFee(gbp(24*100))(listing))
+	require.False(t, MinTotalPriceExclFee(gbp(25*100))(listing))
+	require.True(t, MaxTotalPriceExclFee(gbp(24*100))(listing))
+	require.False(t, MaxTotalPriceExclFee(gbp(23*100))(listing))
+
+	require.True(t, MinTotalPriceInclFee(gbp(27*100))(listing))
+	require.False(t, MinTotalPriceInclFee(gbp(28*100))(listing))
+	require.True(t, MaxTotalPriceInclFee(gbp(27*100))(listing))
+	require.False(t, MaxTotalPriceInclFee(gbp(26*100))(listing))
+
+	require.True(t, MinTicketPriceInclFee(gbp(1350))(listing))
+	require.False(t, MinTicketPriceInclFee(gbp(1351))(listing))
+}
+
+func TestAtOrBelowFaceValuePredicate(t *testing.T) {
+	predicate := AtOrBelowFaceValue()
+
+	// £27 incl fee, £30 face value
+	listing := twigots.TicketListing{
+		NumTickets:         2,
+		TotalPriceExclFee:  gbp(24 * 100),
+		TwicketsFee:        gbp(3 * 100),
+		OriginalTotalPrice: gbp(30 * 100),
+	}
+	require.True(t, predicate(listing))
+
+	// £27 incl fee, £27 face value
+	listing.OriginalTotalPrice = gbp(27 * 100)
+	require.True(t, predicate(listing))
+
+	// £27 incl fee, £24 face value
+	listing.OriginalTotalPrice = gbp(24 * 100)
+	require.False(t, predicate(listing))
+}
+
+func TestMaxFeePercentagePredicate(t *testing.T) {
+	// 15% fee
+	listing := twigots.TicketListing{
+		NumTickets:        2,
+		TotalPriceExclFee: gbp(20 * 100),
+		TwicketsFee:       gbp(3 * 100),
+	}
+
+	require.True(t, MaxFeePercentage(0.15)(listing))
+	require.False(t, MaxFeePercentage(0.1)(listing))
+	require.True(t, MaxFeePercentage(0)(listing))
+}
+
+func TestPricePredicatesWithConverter(t *testing.T) {
+	converter, err := twigots.NewStaticConverter(twigots.CurrencyGBP, map[twigots.Currency]float64{
+		twigots.CurrencyEUR: 1.25,
+	})
+	require.NoError(t, err)
+
+	// €160 total incl fee for 2 tickets - €80 (£64) per ticket incl fee
+	listing := twigots.TicketListing{
+		NumTickets:        2,
+		TotalPriceExclFee: twigots.Price{Currency: twigots.CurrencyEUR, Amount: 150 * 100},
+		TwicketsFee:       twigots.Price{Currency: twigots.CurrencyEUR, Amount: 10 * 100},
+	}
+
+	// Without a converter, prices in a different currency should never match
+	require.False(t, MaxTicketPriceInclFee(gbp(80*100))(listing))
+
+	result := Evaluate(listing, ExplainMaxTicketPriceInclFee(gbp(80*100), WithConverter(converter)))
+	require.True(t, result.Matched)
+	require.Equal(t, "ticket price incl fee £64.00 (€80.00) <= £80.00", result.Verdicts[0].Reason)
+
+	require.False(t, MaxTicketPriceInclFee(gbp(60*100), WithConverter(converter))(listing))
+	require.True(t, MinTotalPriceInclFee(gbp(128*100), WithConverter(converter))(listing))
+	require.False(t, MinTotalPriceInclFee(gbp(129*100), WithConverter(converter))(listing))
+
+	// Prices that cannot be converted should not match
+	listing.TotalPriceExclFee.Currency = twigots.CurrencyUSD
+	listing.TwicketsFee.Currency = twigots.CurrencyUSD
+	result = Evaluate(listing, ExplainMaxTicketPriceInclFee(gbp(80*100), WithConverter(converter)))
+	require.False(t, result.Matched)
+	require.Contains(t, result.Verdicts[0].Reason, "could not be converted")
+}
+
+// gbp creates a price in pounds, with the amount in pence.
+func gbp(amount int) twigots.Price {
+	return twigots.Price{Currency: twigots.CurrencyGBP, Amount: amount}
+}
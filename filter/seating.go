@@ -0,0 +1,445 @@
+package filter
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+const (
+	// Ticket types that can be used with TicketType.
+	// Any other ticket type will be fuzzy matched as is.
+	TicketTypeStanding = "standing"
+	TicketTypeSeated   = "seated"
+	TicketTypeBox      = "box"
+
+	// Default ticket type similarity used when matching ticket types
+	DefaultTicketTypeSimilarity = 0.9
+
+	// Maximum number of letters in a seat label (e.g. section or row) for it to be ordered
+	maxSeatLabelLetters = 3
+)
+
+// ticketTypeKeywords are the keywords that indicate a listing is of a known ticket type.
+// Ticket types are free text on twickets (e.g. "PITCH STANDING & UNRESERVED SEATS"),
+// so a ticket type matches if any of its keywords appear in the listing ticket type.
+var ticketTypeKeywords = map[string][]string{
+	TicketTypeStanding: {"standing", "general admission", "ga", "pitch", "floor", "unreserved"},
+	TicketTypeSeated:   {"seated", "seat", "seats", "seating", "stalls", "circle", "balcony", "reserved"},
+	TicketTypeBox:      {"box", "suite", "hospitality"},
+}
+
+// ticketTypeExcludeKeywords are the keywords that indicate a listing is not of a known ticket type,
+// even if it contains one of its keywords. Standing keywords win over seated keywords, as standing
+// tickets often mention seats e.g. "PITCH STANDING & UNRESERVED SEATS".
+var ticketTypeExcludeKeywords = map[string][]string{
+	TicketTypeSeated: {"standing", "general admission", "ga", "unreserved"},
+}
+
+// ticketTypeMatcher matches a listing ticket type against the keywords of a ticket type.
+type ticketTypeMatcher struct {
+	keywords        []string
+	excludeKeywords []string
+}
+
+// match returns the keyword that matched the listing ticket type, or false if none matched
+// or an exclude keyword matched. ticketType must be normalised and padded with spaces.
+func (m ticketTypeMatcher) match(ticketType string) (string, bool) {
+	for _, excludeKeyword := range m.excludeKeywords {
+		if substringSimilarity(excludeKeyword, ticketType) >= DefaultTicketTypeSimilarity {
+			return "", false
+		}
+	}
+	for _, keyword := range m.keywords {
+		if substringSimilarity(keyword, ticketType) >= DefaultTicketTypeSimilarity {
+			return keyword, true
+		}
+	}
+	return "", false
+}
+
+// TicketType creates a predicate that matches ticket listings with any of the specified ticket types.
+//
+// The known ticket types TicketTypeStanding, TicketTypeSeated and TicketTypeBox will match any ticket type
+// containing a related keyword e.g. "pitch" or "general admission" will match TicketTypeStanding.
+// Standing keywords take precedence over seated keywords, so "PITCH STANDING & UNRESERVED SEATS"
+// will match TicketTypeStanding but not TicketTypeSeated.
+// Any other ticket type is fuzzy matched against the listing ticket type as is.
+//
+// If ticketTypes is empty, any ticket type will match.
+func TicketType(ticketTypes ...string) TicketListingPredicate {
+	matchers, _ := newTicketTypeMatchers(ticketTypes)
+
+	// If no ticket types specified, match any ticket type
+	if len(matchers) == 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		listingTicketType := padKeyword(normaliseString(listing.TicketType))
+		for _, matcher := range matchers {
+			_, ok := matcher.match(listingTicketType)
+			if ok {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// ExplainTicketType creates an explainer for TicketType. See Evaluate.
+func ExplainTicketType(ticketTypes ...string) TicketListingExplainer {
+	matchers, validTicketTypes := newTicketTypeMatchers(ticketTypes)
+
+	// If no ticket types specified, match any ticket type
+	if len(matchers) == 0 {
+		return alwaysExplainer("TicketType")
+	}
+
+	validTicketTypesString := strings.Join(validTicketTypes, ", ")
+	return newExplainer("TicketType", func(listing twigots.TicketListing) (bool, string) {
+		listingTicketType := padKeyword(normaliseString(listing.TicketType))
+		for _, matcher := range matchers {
+			keyword, ok := matcher.match(listingTicketType)
+			if ok {
+				return true, fmt.Sprintf("%q matches %q", listing.TicketType, strings.TrimSpace(keyword))
+			}
+		}
+		return false, fmt.Sprintf("%q does not match any of [%s]", listing.TicketType, validTicketTypesString)
+	})
+}
+
+// newTicketTypeMatchers creates the matchers of ticket types, ignoring empty ones.
+// The normalised ticket types matched are also returned.
+func newTicketTypeMatchers(ticketTypes []string) ([]ticketTypeMatcher, []string) {
+	matchers := make([]ticketTypeMatcher, 0, len(ticketTypes))
+	validTicketTypes := make([]string, 0, len(ticketTypes))
+	for _, ticketType := range ticketTypes {
+		ticketType = normaliseString(ticketType)
+		if ticketType == "" {
+			continue
+		}
+		validTicketTypes = append(validTicketTypes, ticketType)
+
+		typeKeywords, ok := ticketTypeKeywords[ticketType]
+		if !ok {
+			typeKeywords = []string{ticketType}
+		}
+
+		matchers = append(matchers, ticketTypeMatcher{
+			keywords:        padKeywords(typeKeywords),
+			excludeKeywords: padKeywords(ticketTypeExcludeKeywords[ticketType]),
+		})
+	}
+	return matchers, validTicketTypes
+}
+
+// padKeywords pads keywords with spaces, so they only match whole words.
+func padKeywords(keywords []string) []string {
+	paddedKeywords := make([]string, 0, len(keywords))
+	for _, keyword := range keywords {
+		paddedKeywords = append(paddedKeywords, padKeyword(keyword))
+	}
+	return paddedKeywords
+}
+
+// padKeyword pads a keyword with spaces, so it only matches whole words.
+func padKeyword(keyword string) string {
+	return " " + keyword + " "
+}
+
+// SeatAssigned creates a predicate that matches ticket listings with assigned seats.
+//
+// There is no predicate for whether the seats of a listing are together, as twickets listings
+// only include the section and row of the tickets, not the seat numbers.
+func SeatAssigned() TicketListingPredicate {
+	return func(listing twigots.TicketListing) bool {
+		return listing.SeatAssigned
+	}
+}
+
+// ExplainSeatAssigned creates an explainer for SeatAssigned. See Evaluate.
+func ExplainSeatAssigned() TicketListingExplainer {
+	return newExplainer("SeatAssigned", func(listing twigots.TicketListing) (bool, string) {
+		if listing.SeatAssigned {
+			return true, "seat assigned"
+		}
+		return false, "seat not assigned"
+	})
+}
+
+// AcceptsOffers creates a predicate that matches ticket listings where the seller will consider offers.
+func AcceptsOffers() TicketListingPredicate {
+	return func(listing twigots.TicketListing) bool {
+		return listing.SellerWillConsiderOffers
+	}
+}
+
+// ExplainAcceptsOffers creates an explainer for AcceptsOffers. See Evaluate.
+func ExplainAcceptsOffers() TicketListingExplainer {
+	return newExplainer("AcceptsOffers", func(listing twigots.TicketListing) (bool, string) {
+		if listing.SellerWillConsiderOffers {
+			return true, "seller will consider offers"
+		}
+		return false, "seller will not consider offers"
+	})
+}
+
+// Section creates a predicate that matches ticket listings in any of the specified sections.
+//
+// Sections are matched case-insensitively. Ranges of sections can be specified
+// e.g. "101-105" or "A-F". Listings without a section will not match.
+//
+// If sections is empty, any section will match (including no section).
+func Section(sections ...string) TicketListingPredicate {
+	return seatLabelPredicate(sections, true, listingSection)
+}
+
+// ExplainSection creates an explainer for Section. See Evaluate.
+func ExplainSection(sections ...string) TicketListingExplainer {
+	return seatLabelExplainer("Section", "section", sections, true, listingSection)
+}
+
+// NotSection creates a predicate that matches ticket listings that are not in any of the specified sections.
+//
+// Sections are matched case-insensitively. Ranges of sections can be specified
+// e.g. "101-105" or "A-F". Listings without a section will match.
+//
+// If sections is empty, any section will match (including no section).
+func NotSection(sections ...string) TicketListingPredicate {
+	return seatLabelPredicate(sections, false, listingSection)
+}
+
+// ExplainNotSection creates an explainer for NotSection. See Evaluate.
+func ExplainNotSection(sections ...string) TicketListingExplainer {
+	return seatLabelExplainer("NotSection", "section", sections, false, listingSection)
+}
+
+// Row creates a predicate that matches ticket listings in any of the specified rows.
+//
+// Rows are matched case-insensitively. Ranges of rows can be specified
+// e.g. "A-F" or "1-10". Listings without a row will not match.
+//
+// If rows is empty, any row will match (including no row).
+func Row(rows ...string) TicketListingPredicate {
+	return seatLabelPredicate(rows, true, listingRow)
+}
+
+// ExplainRow creates an explainer for Row. See Evaluate.
+func ExplainRow(rows ...string) TicketListingExplainer {
+	return seatLabelExplainer("Row", "row", rows, true, listingRow)
+}
+
+// NotRow creates a predicate that matches ticket listings that are not in any of the specified rows.
+//
+// Rows are matched case-insensitively. Ranges of rows can be specified
+// e.g. "A-F" or "1-10". Listings without a row will match.
+//
+// If rows is empty, any row will match (including no row).
+func NotRow(rows ...string) TicketListingPredicate {
+	return seatLabelPredicate(rows, false, listingRow)
+}
+
+// ExplainNotRow creates an explainer for NotRow. See Evaluate.
+func ExplainNotRow(rows ...string) TicketListingExplainer {
+	return seatLabelExplainer("NotRow", "row", rows, false, listingRow)
+}
+
+// SeatLabelDistance calculates the distance between two seat labels (e.g. sections or rows).
+// Labels can either be numbers (e.g. "12") or letters (e.g. "A" or "AA"), with letters ordered as
+// A, B, ..., Z, AA, AB etc.
+//
+// Returns false if either label is not a number or letters, or if the labels are of different kinds.
+func SeatLabelDistance(label, otherLabel string) (int, bool) {
+	parsedLabel, ok := parseSeatLabel(label)
+	if !ok {
+		return 0, false
+	}
+
+	parsedOtherLabel, ok := parseSeatLabel(otherLabel)
+	if !ok || parsedLabel.numeric != parsedOtherLabel.numeric {
+		return 0, false
+	}
+
+	distance := parsedLabel.index - parsedOtherLabel.index
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance, true
+}
+
+// seatLabelPredicate creates a predicate that matches ticket listings with a seat label (e.g. section or row)
+// that is (if allow is true) or is not (if allow is false) matched by any of the label specs.
+func seatLabelPredicate(
+	labelSpecs []string,
+	allow bool,
+	listingLabel func(twigots.TicketListing) string,
+) TicketListingPredicate {
+	labelRanges, _ := parseSeatLabelRanges(labelSpecs)
+
+	// If no labels specified, match any label
+	if len(labelRanges) == 0 {
+		return alwaysPredicate
+	}
+
+	return func(listing twigots.TicketListing) bool {
+		label := listingLabel(listing)
+		for _, labelRange := range labelRanges {
+			if labelRange.contains(label) {
+				return allow
+			}
+		}
+		return !allow
+	}
+}
+
+// seatLabelExplainer creates a named explainer that matches ticket listings with a seat label (e.g. section or row)
+// that is (if allow is true) or is not (if allow is false) matched by any of the label specs.
+// labelDescription is used to describe the label when explaining the verdict.
+func seatLabelExplainer(
+	name string,
+	labelDescription string,
+	labelSpecs []string,
+	allow bool,
+	listingLabel func(twigots.TicketListing) string,
+) TicketListingExplainer {
+	labelRanges, validLabelSpecs := parseSeatLabelRanges(labelSpecs)
+
+	// If no labels specified, match any label
+	if len(labelRanges) == 0 {
+		return alwaysExplainer(name)
+	}
+
+	validLabelSpecsString := strings.Join(validLabelSpecs, ", ")
+	return newExplainer(name, func(listing twigots.TicketListing) (bool, string) {
+		label := listingLabel(listing)
+		for _, labelRange := range labelRanges {
+			if labelRange.contains(label) {
+				return allow, fmt.Sprintf("%s %q in [%s]", labelDescription, label, validLabelSpecsString)
+			}
+		}
+		return !allow, fmt.Sprintf("%s %q not in [%s]", labelDescription, label, validLabelSpecsString)
+	})
+}
+
+// parseSeatLabelRanges parses seat label ranges, ignoring empty ones.
+// The label specs of the ranges parsed are also returned.
+func parseSeatLabelRanges(labelSpecs []string) ([]seatLabelRange, []string) {
+	labelRanges := make([]seatLabelRange, 0, len(labelSpecs))
+	validLabelSpecs := make([]string, 0, len(labelSpecs))
+	for _, labelSpec := range labelSpecs {
+		labelRange, ok := parseSeatLabelRange(labelSpec)
+		if ok {
+			labelRanges = append(labelRanges, labelRange)
+			validLabelSpecs = append(validLabelSpecs, labelSpec)
+		}
+	}
+	return labelRanges, validLabelSpecs
+}
+
+// listingSection gets the section of a listing.
+func listingSection(listing twigots.TicketListing) string {
+	return listing.Section
+}
+
+// listingRow gets the row of a listing.
+func listingRow(listing twigots.TicketListing) string {
+	return listing.Row
+}
+
+// seatLabelRange is a range of seat labels (e.g. sections or rows) such as "A-F" or "1-10".
+// A single label is represented as a range with the same start and end.
+type seatLabelRange struct {
+	label      string // Used when the range is a single label that cannot be ordered
+	start, end seatLabel
+}
+
+// parseSeatLabelRange parses a seat label range such as "A-F", "1-10" or "A".
+// Returns false if the spec is empty.
+func parseSeatLabelRange(spec string) (seatLabelRange, bool) {
+	spec = normaliseSeatLabel(spec)
+	if spec == "" {
+		return seatLabelRange{}, false
+	}
+
+	// Parse range if both start and end are labels of the same kind
+	startString, endString, isRange := strings.Cut(spec, "-")
+	if isRange {
+		start, startOk := parseSeatLabel(startString)
+		end, endOk := parseSeatLabel(endString)
+		if startOk && endOk && start.numeric == end.numeric {
+			if start.index > end.index {
+				start, end = end, start
+			}
+			return seatLabelRange{start: start, end: end}, true
+		}
+	}
+
+	// Otherwise the spec is a single label
+	label, ok := parseSeatLabel(spec)
+	if !ok {
+		return seatLabelRange{label: spec}, true
+	}
+	return seatLabelRange{start: label, end: label}, true
+}
+
+// contains checks whether the seat label range contains the label.
+func (r seatLabelRange) contains(label string) bool {
+	label = normaliseSeatLabel(label)
+	if label == "" {
+		return false
+	}
+
+	if r.label != "" {
+		return r.label == label
+	}
+
+	parsedLabel, ok := parseSeatLabel(label)
+	if !ok || parsedLabel.numeric != r.start.numeric {
+		return false
+	}
+	return parsedLabel.index >= r.start.index && parsedLabel.index <= r.end.index
+}
+
+// seatLabel is an orderable seat label (e.g. section or row).
+type seatLabel struct {
+	numeric bool
+	index   int
+}
+
+// parseSeatLabel parses a seat label that is either a number (e.g. "12") or letters (e.g. "A" or "AA").
+// Letter labels are ordered as A, B, ..., Z, AA, AB etc. and can be at most 3 letters long,
+// as longer labels are likely to be names (e.g. "Upper Tier").
+// Returns false if the label is neither.
+func parseSeatLabel(label string) (seatLabel, bool) {
+	label = normaliseSeatLabel(label)
+	if label == "" {
+		return seatLabel{}, false
+	}
+
+	number, err := strconv.Atoi(label)
+	if err == nil {
+		return seatLabel{numeric: true, index: number}, true
+	}
+
+	if len(label) > maxSeatLabelLetters {
+		return seatLabel{}, false
+	}
+
+	index := 0
+	for _, char := range label {
+		if char < 'A' || char > 'Z' {
+			return seatLabel{}, false
+		}
+		index = index*26 + int(char-'A') + 1
+	}
+	return seatLabel{index: index}, true
+}
+
+// normaliseSeatLabel normalises a seat label by removing whitespace and converting to upper case.
+func normaliseSeatLabel(label string) string {
+	label = spaceRegex.ReplaceAllString(label, "")
+	return strings.ToUpper(label)
+}
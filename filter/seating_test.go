@@ -0,0 +1,109 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTicketTypePredicate(t *testing.T) {
+	standingListing := twigots.TicketListing{TicketType: "PITCH STANDING & UNRESERVED SEATS"}
+	seatedListing := twigots.TicketListing{TicketType: "Stalls with good view"}
+	boxListing := twigots.TicketListing{TicketType: "Private Box (12 guests)"}
+
+	// Known ticket types should match their keywords
+	predicate := TicketType(TicketTypeStanding)
+	require.True(t, predicate(standingListing))
+	require.False(t, predicate(seatedListing))
+	require.False(t, predicate(boxListing))
+
+	predicate = TicketType(TicketTypeSeated)
+	require.True(t, predicate(seatedListing))
+	require.True(t, predicate(twigots.TicketListing{TicketType: "Reserved Seating Level 1"}))
+	require.False(t, predicate(boxListing))
+
+	// Standing keywords should win over seated keywords
+	require.False(t, predicate(standingListing))
+	require.False(t, predicate(twigots.TicketListing{TicketType: "General Admission (no seats)"}))
+
+	predicate = TicketType(TicketTypeBox, TicketTypeSeated)
+	require.True(t, predicate(seatedListing))
+	require.True(t, predicate(boxListing))
+
+	// Unknown ticket types should be fuzzy matched
+	predicate = TicketType("Good View")
+	require.True(t, predicate(seatedListing))
+	require.False(t, predicate(standingListing))
+
+	// No ticket types should match anything
+	predicate = TicketType()
+	require.True(t, predicate(boxListing))
+}
+
+func TestSectionPredicate(t *testing.T) {
+	listing := twigots.TicketListing{Section: "103"}
+	noSectionListing := twigots.TicketListing{}
+
+	predicate := Section("101-105")
+	require.True(t, predicate(listing))
+	require.False(t, predicate(noSectionListing))
+
+	predicate = Section("104", "B")
+	require.False(t, predicate(listing))
+
+	predicate = NotSection("101-105")
+	require.False(t, predicate(listing))
+	require.True(t, predicate(noSectionListing))
+
+	predicate = NotSection("Upper Tier")
+	require.True(t, predicate(listing))
+	require.False(t, predicate(twigots.TicketListing{Section: "upper  tier"}))
+}
+
+func TestRowPredicate(t *testing.T) {
+	predicate := Row("A-F")
+	require.True(t, predicate(twigots.TicketListing{Row: "a"}))
+	require.True(t, predicate(twigots.TicketListing{Row: "F"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "G"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "AA"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "3"}))
+	require.False(t, predicate(twigots.TicketListing{}))
+
+	// Reversed ranges and letters after Z
+	predicate = Row("AC-Z")
+	require.True(t, predicate(twigots.TicketListing{Row: "AB"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "Y"}))
+
+	// Labels longer than 3 letters are names, so are not ordered
+	predicate = Row("A-ZZZ")
+	require.True(t, predicate(twigots.TicketListing{Row: "ZZZ"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "Stalls"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "Front"}))
+
+	predicate = NotRow("1-10", "ZZ")
+	require.False(t, predicate(twigots.TicketListing{Row: "10"}))
+	require.False(t, predicate(twigots.TicketListing{Row: "zz"}))
+	require.True(t, predicate(twigots.TicketListing{Row: "11"}))
+	require.True(t, predicate(twigots.TicketListing{}))
+}
+
+func TestSeatLabelDistance(t *testing.T) {
+	distance, ok := SeatLabelDistance("A", "f")
+	require.True(t, ok)
+	require.Equal(t, 5, distance)
+
+	distance, ok = SeatLabelDistance("AA", "Z")
+	require.True(t, ok)
+	require.Equal(t, 1, distance)
+
+	distance, ok = SeatLabelDistance("110", "101")
+	require.True(t, ok)
+	require.Equal(t, 9, distance)
+
+	_, ok = SeatLabelDistance("A", "1")
+	require.False(t, ok)
+
+	_, ok = SeatLabelDistance("Upper Tier", "A")
+	require.False(t, ok)
+}
@@ -0,0 +1,144 @@
+package filter
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/hbollon/go-edlib"
+	"github.com/orsinium-labs/enum"
+)
+
+var (
+	similarityStrategy = enum.NewBuilder[string, SimilarityStrategy]()
+
+	// SimilarityAlignment aligns the words of the desired name with the words of the listing name, in order,
+	// allowing words to be misspelt. This is the default, and the strategy used by EventName and NameSimilarity.
+	SimilarityAlignment = similarityStrategy.Add(SimilarityStrategy{"ALIGNMENT"})
+	// SimilarityTokenSet matches each word of the desired name with a word of the listing name, in any order,
+	// allowing words to be misspelt e.g. "Eilish Billie" matches "Billie Eilish".
+	SimilarityTokenSet = similarityStrategy.Add(SimilarityStrategy{"TOKEN_SET"})
+	// SimilarityPhonetic aligns the words of the desired name with the words of the listing name, in order,
+	// comparing how words sound (using Double Metaphone) rather than how they are spelt
+	// e.g. "Beyonse" matches "Beyoncé".
+	SimilarityPhonetic = similarityStrategy.Add(SimilarityStrategy{"PHONETIC"})
+	// SimilarityCombined uses the highest similarity of the alignment, token set and phonetic strategies.
+	SimilarityCombined = similarityStrategy.Add(SimilarityStrategy{"COMBINED"})
+
+	SimilarityStrategies = similarityStrategy.Enum()
+)
+
+// SimilarityStrategy is the strategy used to calculate the similarity of names.
+type SimilarityStrategy enum.Member[string]
+
+func (s *SimilarityStrategy) UnmarshalJSON(data []byte) error {
+	var strategyString string
+	err := json.Unmarshal(data, &strategyString)
+	if err != nil {
+		return err
+	}
+
+	return s.UnmarshalText([]byte(strategyString))
+}
+
+func (s SimilarityStrategy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value)
+}
+
+func (s *SimilarityStrategy) UnmarshalText(data []byte) error {
+	strategyString := string(data)
+
+	strategy := SimilarityStrategies.Parse(strategyString)
+	if strategy == nil {
+		return fmt.Errorf("similarity strategy '%s' is not valid", strategyString)
+	}
+
+	*s = *strategy
+	return nil
+}
+
+// usesSpelling returns whether the strategy compares how words are spelt.
+// Unset strategies are treated as SimilarityAlignment.
+func (s SimilarityStrategy) usesSpelling() bool {
+	return s != SimilarityPhonetic
+}
+
+// usesPhonetics returns whether the strategy compares how words sound.
+func (s SimilarityStrategy) usesPhonetics() bool {
+	return s == SimilarityPhonetic || s == SimilarityCombined
+}
+
+// wordSimilarity calculates the similarity of two words in the same way as substringSimilarity.
+func wordSimilarity(word, otherWord string) float64 {
+	if word == otherWord {
+		return 1
+	}
+	return stringSimilarity(word, otherWord)
+}
+
+// phoneticWordSimilarity calculates the similarity of how two words sound, using the highest similarity of their
+// Double Metaphone codes. If either word has no code (e.g. it is a number), their spelling is compared instead.
+func phoneticWordSimilarity(word, otherWord string, codes, otherCodes [2]string) float64 {
+	if word == otherWord {
+		return 1
+	}
+	if codes[0] == "" || otherCodes[0] == "" {
+		return stringSimilarity(word, otherWord)
+	}
+
+	var similarity float64
+	for _, code := range codes {
+		for _, otherCode := range otherCodes {
+			if code == otherCode {
+				return 1
+			}
+			similarity = max(similarity, stringSimilarity(code, otherCode))
+		}
+	}
+	return similarity
+}
+
+// stringSimilarity calculates the optimal string alignment Damerau-Levenshtein similarity of two strings.
+func stringSimilarity(s, other string) float64 {
+	similarity, err := edlib.StringsSimilarity(s, other, edlib.DamerauLevenshtein)
+	if err != nil {
+		// An error will never occur if a valid similarity algorithm is used.
+		// If an error does occur (due to an error in the code), panic so we catch it.
+		panic(err)
+	}
+	return float64(similarity)
+}
+
+// matchWordSets matches each substring word with a different target word, in any order, using precalculated
+// word similarities, and returns the average similarity across all substring words.
+// similarities is a flat numSubWords x numTargetWords matrix.
+//
+// Words are matched greedily, with the most similar pair of unmatched words matched first.
+// Substring words that cannot be matched (as there are more substring words than target words) have a similarity
+// of 0.
+func matchWordSets(similarities []float64, numSubWords, numTargetWords int) float64 {
+	subWordUsed := make([]bool, numSubWords)
+	targetWordUsed := make([]bool, numTargetWords)
+
+	var totalSimilarity float64
+	for range min(numSubWords, numTargetWords) {
+		bestSubWord, bestTargetWord := -1, -1
+		bestSimilarity := -1.0
+		for i := 0; i < numSubWords; i++ {
+			if subWordUsed[i] {
+				continue
+			}
+			for j := 0; j < numTargetWords; j++ {
+				similarity := similarities[i*numTargetWords+j]
+				if !targetWordUsed[j] && similarity > bestSimilarity {
+					bestSubWord, bestTargetWord, bestSimilarity = i, j, similarity
+				}
+			}
+		}
+
+		subWordUsed[bestSubWord] = true
+		targetWordUsed[bestTargetWord] = true
+		totalSimilarity += bestSimilarity
+	}
+
+	return totalSimilarity / float64(numSubWords)
+}
@@ -0,0 +1,80 @@
+package filter
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimilarityStrategies(t *testing.T) {
+	testCases := []struct {
+		desiredEventName string
+		actualEventName  string
+		// Expected matches for alignment, token set, phonetic and combined strategies
+		matches [4]bool
+	}{
+		{"Billie Eilish", "Billie Eilish | Hit Me Hard and Soft", [4]bool{true, true, true, true}},
+		{"Eilish Billie", "Billie Eilish | Hit Me Hard and Soft", [4]bool{false, true, false, true}},
+		{"Beyonse", "Beyoncé: Cowboy Carter Tour", [4]bool{false, false, true, true}},
+		{"Nite Owls", "Night Owls", [4]bool{false, false, true, true}},
+		{"Oasis", "Oasish", [4]bool{false, false, false, false}},
+		{"Coldplay", "Coldplace", [4]bool{false, false, false, false}},
+		{"The Who", "The The", [4]bool{false, false, false, false}},
+	}
+
+	strategies := []SimilarityStrategy{
+		SimilarityAlignment,
+		SimilarityTokenSet,
+		SimilarityPhonetic,
+		SimilarityCombined,
+	}
+
+	for _, testCase := range testCases {
+		listing := twigots.TicketListing{Event: twigots.Event{Name: testCase.actualEventName}}
+
+		for idx, strategy := range strategies {
+			options := EventNameOptions{SimilarityStrategy: strategy}
+
+			// Check both with and without pruning
+			matcher := NewNameMatcherWithOptions(testCase.desiredEventName, options)
+			similarity := matcher.Similarity(testCase.actualEventName)
+			require.Equal(
+				t, testCase.matches[idx], similarity >= DefaultEventNameSimilarity,
+				"similarity of %q to %q using %s", testCase.desiredEventName, testCase.actualEventName, strategy.Value,
+			)
+			require.Equal(
+				t, testCase.matches[idx], matcher.Matches(listing),
+				"match of %q to %q using %s", testCase.desiredEventName, testCase.actualEventName, strategy.Value,
+			)
+		}
+	}
+}
+
+func TestSimilarityStrategyDefault(t *testing.T) {
+	// Unset strategy is the same as alignment
+	for _, watchedEventName := range testWatchedEventNames {
+		matcher := NewNameMatcherWithOptions(watchedEventName, EventNameOptions{})
+		alignmentMatcher := NewNameMatcherWithOptions(
+			watchedEventName,
+			EventNameOptions{SimilarityStrategy: SimilarityAlignment},
+		)
+		for _, listingEventName := range testListingEventNames {
+			require.Equal(t, alignmentMatcher.Similarity(listingEventName), matcher.Similarity(listingEventName))
+		}
+	}
+}
+
+func TestSimilarityStrategyJSON(t *testing.T) {
+	var options struct {
+		Strategy SimilarityStrategy `json:"strategy"`
+	}
+
+	err := json.Unmarshal([]byte(`{"strategy": "TOKEN_SET"}`), &options)
+	require.NoError(t, err)
+	require.Equal(t, SimilarityTokenSet, options.Strategy)
+
+	err = json.Unmarshal([]byte(`{"strategy": "SOUNDEX"}`), &options)
+	require.Error(t, err)
+}
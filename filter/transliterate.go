@@ -0,0 +1,128 @@
+package filter
+
+import "strings"
+
+// Transliterations of lower case letters that are not removed by the accent transformer to a-z alternatives.
+// This includes Latin letters that are not letters with an accent (e.g. æ and ø), and Cyrillic and Greek letters.
+//
+// Precomposed letters that would otherwise be changed by the accent transformer (e.g. Cyrillic й and Greek ά)
+// are included, as their accents change how they are transliterated.
+var transliterations = map[string]string{
+	// Latin
+	"æ": "ae",
+	"đ": "d",
+	"ð": "d",
+	"ħ": "h",
+	"ı": "i",
+	"ĸ": "k",
+	"ŀ": "l",
+	"ł": "l",
+	"ŋ": "ng",
+	"ø": "o",
+	"œ": "oe",
+	"ß": "ss",
+	"ſ": "s",
+	"þ": "th",
+	"ŧ": "t",
+
+	// Cyrillic
+	"а": "a",
+	"б": "b",
+	"в": "v",
+	"г": "g",
+	"ґ": "g",
+	"д": "d",
+	"ђ": "dj",
+	"е": "e",
+	"ё": "e",
+	"є": "ye",
+	"ж": "zh",
+	"з": "z",
+	"и": "i",
+	"і": "i",
+	"ї": "yi",
+	"й": "y",
+	"ј": "j",
+	"к": "k",
+	"л": "l",
+	"љ": "lj",
+	"м": "m",
+	"н": "n",
+	"њ": "nj",
+	"о": "o",
+	"п": "p",
+	"р": "r",
+	"с": "s",
+	"т": "t",
+	"ћ": "c",
+	"у": "u",
+	"ў": "u",
+	"ф": "f",
+	"х": "kh",
+	"ц": "ts",
+	"ч": "ch",
+	"џ": "dz",
+	"ш": "sh",
+	"щ": "shch",
+	"ъ": "",
+	"ы": "y",
+	"ь": "",
+	"э": "e",
+	"ю": "yu",
+	"я": "ya",
+
+	// Greek
+	"α": "a",
+	"ά": "a",
+	"β": "v",
+	"γ": "g",
+	"δ": "d",
+	"ε": "e",
+	"έ": "e",
+	"ζ": "z",
+	"η": "i",
+	"ή": "i",
+	"θ": "th",
+	"ι": "i",
+	"ί": "i",
+	"ϊ": "i",
+	"ΐ": "i",
+	"κ": "k",
+	"λ": "l",
+	"μ": "m",
+	"ν": "n",
+	"ξ": "x",
+	"ο": "o",
+	"ό": "o",
+	"π": "p",
+	"ρ": "r",
+	"σ": "s",
+	"ς": "s",
+	"τ": "t",
+	"υ": "y",
+	"ύ": "y",
+	"ϋ": "y",
+	"ΰ": "y",
+	"φ": "f",
+	"χ": "ch",
+	"ψ": "ps",
+	"ω": "o",
+	"ώ": "o",
+}
+
+// Replacer to transliterate lower case letters. See transliterations.
+var transliterator = newTransliterator()
+
+func newTransliterator() *strings.Replacer {
+	oldNew := make([]string, 0, 2*len(transliterations))
+	for letter, transliteration := range transliterations {
+		oldNew = append(oldNew, letter, transliteration)
+	}
+	return strings.NewReplacer(oldNew...)
+}
+
+// transliterate transliterates a lower case string, replacing letters that cannot be represented
+// using a-z with a-z alternatives. The string must be NFC normalised.
+func transliterate(s string) string {
+	return transliterator.Replace(s)
+}
@@ -0,0 +1,203 @@
+package filter
+
+import (
+	"maps"
+	"unicode/utf8"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// WatchEntry is a name to watch for, and the minimum similarity required for a listing event name to match it.
+type WatchEntry struct {
+	Name string
+
+	// MinimumSimilarity is handled in the same way as EventName.
+	MinimumSimilarity float64
+}
+
+// WatchMatch is a watch entry that matched a ticket listing, and the similarity of the listing event name.
+type WatchMatch struct {
+	Entry      WatchEntry
+	Similarity float64
+}
+
+// Watchlist is an index of many watch entries, used to efficiently find which entries match a ticket listing.
+//
+// Rather than calculating the similarity of every entry name to each listing event name, a watchlist uses an
+// inverted index of the character trigrams in each entry name (and its aliases) to find candidate entries that share
+// at least one trigram with the listing event name (or its aliases). Only these candidates then have their similarity
+// calculated, in the same way as EventName, including the built-in aliases (unless created with other aliases
+// using NewWatchlistWithOptions).
+//
+// A watchlist returns the same matches as EventName. A listing event name can be similar enough to match an entry
+// without sharing any trigrams with it if the entry has a low minimum similarity or short words (e.g. "Blur" and
+// "Bulr"), so these entries are always scored, and are not sped up by the index.
+//
+// A Watchlist is safe for concurrent use.
+type Watchlist struct {
+	entries  []WatchEntry
+	matchers []*NameMatcher
+	aliases  *Aliases
+
+	// index of trigrams to the indices of entries containing them
+	index map[string][]int
+	// alwaysCandidates are the indices of entries that must always be scored
+	alwaysCandidates []int
+}
+
+// WatchlistOptions are options for a watchlist.
+type WatchlistOptions struct {
+	// Aliases of event and artist names to expand before similarity is calculated.
+	// See EventNameOptions.Aliases.
+	//
+	// Set to nil to not use aliases. Use DefaultAliases to use the built-in aliases.
+	Aliases *Aliases
+}
+
+// NewWatchlist creates a watchlist of the entries specified, using the built-in aliases.
+//
+// If an entry name is empty, it will match any listing with a similarity of 1.
+func NewWatchlist(entries ...WatchEntry) *Watchlist {
+	return NewWatchlistWithOptions(entries, WatchlistOptions{Aliases: DefaultAliases()})
+}
+
+// NewWatchlistWithOptions creates a watchlist of the entries specified, using the options specified.
+// Entries match in the same way as EventNameWithOptions with the same aliases.
+//
+// If an entry name is empty, it will match any listing with a similarity of 1.
+func NewWatchlistWithOptions(entries []WatchEntry, options WatchlistOptions) *Watchlist {
+	watchlist := &Watchlist{
+		entries:  make([]WatchEntry, 0, len(entries)),
+		matchers: make([]*NameMatcher, 0, len(entries)),
+		aliases:  options.Aliases,
+		index:    make(map[string][]int),
+	}
+
+	for idx, entry := range entries {
+		matcher := NewNameMatcherWithOptions(entry.Name, EventNameOptions{
+			MinimumSimilarity: entry.MinimumSimilarity,
+			Aliases:           options.Aliases,
+		})
+		watchlist.entries = append(watchlist.entries, entry)
+		watchlist.matchers = append(watchlist.matchers, matcher)
+
+		if watchlistAlwaysCandidate(matcher) {
+			watchlist.alwaysCandidates = append(watchlist.alwaysCandidates, idx)
+			continue
+		}
+
+		// Index the trigrams of all names, so listings matching any alias are candidates
+		entryTrigrams := make(map[string]struct{})
+		for _, name := range matcher.names {
+			maps.Copy(entryTrigrams, nameTrigrams(name))
+		}
+		for trigram := range entryTrigrams {
+			watchlist.index[trigram] = append(watchlist.index[trigram], idx)
+		}
+	}
+
+	return watchlist
+}
+
+// Len returns the number of entries in the watchlist.
+func (w *Watchlist) Len() int {
+	return len(w.entries)
+}
+
+// Match finds the watch entries that match a ticket listing event name.
+// Matches are returned in the order the entries were provided.
+func (w *Watchlist) Match(listing twigots.TicketListing) []WatchMatch {
+	targets := aliasTargetNames(w.aliases, listing.Event.Name)
+
+	// Find candidate entries
+	isCandidate := make([]bool, len(w.entries))
+	for _, idx := range w.alwaysCandidates {
+		isCandidate[idx] = true
+	}
+	for _, target := range targets {
+		for trigram := range nameTrigrams(target) {
+			for _, idx := range w.index[trigram] {
+				isCandidate[idx] = true
+			}
+		}
+	}
+
+	// Score candidate entries
+	var matches []WatchMatch
+	for idx, candidate := range isCandidate {
+		if !candidate {
+			continue
+		}
+
+		matcher := w.matchers[idx]
+		if matcher.eventName == "" {
+			matches = append(matches, WatchMatch{Entry: w.entries[idx], Similarity: 1})
+			continue
+		}
+
+		// Get the highest similarity of any name to any target that matches
+		var bestSimilarity float64
+		var matched bool
+		for _, name := range matcher.names {
+			for _, target := range targets {
+				similarity, nameMatched := matcher.similarity(name, target, true)
+				if nameMatched {
+					bestSimilarity = max(bestSimilarity, similarity)
+					matched = true
+				}
+			}
+		}
+		if matched {
+			matches = append(matches, WatchMatch{Entry: w.entries[idx], Similarity: bestSimilarity})
+		}
+	}
+
+	return matches
+}
+
+// Matches returns whether a ticket listing matches any of the watch entries.
+// This allows a watchlist to be used as a predicate e.g. FilterTicketListings(listings, watchlist.Matches).
+func (w *Watchlist) Matches(listing twigots.TicketListing) bool {
+	return len(w.Match(listing)) > 0
+}
+
+// watchlistAlwaysCandidate returns whether the entry of a matcher must always be scored, as a listing event name
+// could match one of its names without sharing any trigrams with it. See disjointSimilarityBound.
+func watchlistAlwaysCandidate(matcher *NameMatcher) bool {
+	for _, name := range matcher.names {
+		if len(name.words) == 0 ||
+			disjointSimilarityBound(name) >= matcher.minimumSimilarity-similarityBoundTolerance {
+			return true
+		}
+	}
+	return false
+}
+
+// disjointSimilarityBound calculates an upper bound of the similarity of a normalised name to any name that does not
+// share any trigrams with it (see nameTrigrams).
+//
+// A word of length n has n trigrams, and a single edit (e.g. a substitution or transposition) changes at most 4 of
+// them, so a word that shares no trigrams with another word is at least ceil(n/4) edits from it. The similarity of
+// the words is therefore at most n/(n+ceil(n/4)). The bound of a name is the average bound of its words.
+func disjointSimilarityBound(name normalisedName) float64 {
+	var bound float64
+	for _, word := range name.words {
+		length := utf8.RuneCountInString(word)
+		minEdits := (length + 3) / 4
+		bound += float64(length) / float64(length+minEdits)
+	}
+	return bound / float64(len(name.words))
+}
+
+// nameTrigrams returns the set of character trigrams in each word of a normalised name.
+// Each word has a space added on either side, so words shorter than three characters still have trigrams.
+func nameTrigrams(name normalisedName) map[string]struct{} {
+	trigrams := make(map[string]struct{})
+	for _, word := range name.words {
+		paddedWord := []rune(" " + word + " ")
+		for idx := 0; idx+3 <= len(paddedWord); idx++ {
+			trigrams[string(paddedWord[idx:idx+3])] = struct{}{}
+		}
+	}
+	return trigrams
+}
@@ -0,0 +1,139 @@
+package filter
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWatchlistMatchesEventName(t *testing.T) {
+	for _, minimumSimilarity := range []float64{0.5, 0.7, 0.8, 0.9, 1} {
+		entries := make([]WatchEntry, 0, len(testWatchedEventNames))
+		for _, watchedEventName := range testWatchedEventNames {
+			entries = append(entries, WatchEntry{Name: watchedEventName, MinimumSimilarity: minimumSimilarity})
+		}
+		watchlist := NewWatchlist(entries...)
+		require.Equal(t, len(entries), watchlist.Len())
+
+		for _, listingEventName := range testListingEventNames {
+			listing := twigots.TicketListing{Event: twigots.Event{Name: listingEventName}}
+
+			// Get expected matches using event name predicates
+			var expectedMatches []WatchMatch
+			for _, entry := range entries {
+				if EventName(entry.Name, entry.MinimumSimilarity)(listing) {
+					expectedMatches = append(expectedMatches, WatchMatch{
+						Entry:      entry,
+						Similarity: NewNameMatcher(entry.Name, entry.MinimumSimilarity).Similarity(listingEventName),
+					})
+				}
+			}
+
+			require.Equal(
+				t, expectedMatches, watchlist.Match(listing),
+				"matches of %q with similarity %v", listingEventName, minimumSimilarity,
+			)
+			require.Equal(t, len(expectedMatches) > 0, watchlist.Matches(listing))
+		}
+	}
+}
+
+func TestWatchlistEntryThresholds(t *testing.T) {
+	watchlist := NewWatchlist(
+		WatchEntry{Name: "Oasis", MinimumSimilarity: 0.9},
+		WatchEntry{Name: "Oasis", MinimumSimilarity: 0.8},
+		WatchEntry{Name: "Taylor Swift", MinimumSimilarity: 1},
+	)
+
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "Oasish"}}
+	matches := watchlist.Match(listing)
+	require.Len(t, matches, 1)
+	require.Equal(t, WatchEntry{Name: "Oasis", MinimumSimilarity: 0.8}, matches[0].Entry)
+	require.InDelta(t, 0.83, matches[0].Similarity, 0.01)
+
+	listing = twigots.TicketListing{Event: twigots.Event{Name: "Taylor Swift | The Eras Tour"}}
+	matches = watchlist.Match(listing)
+	require.Equal(t, []WatchMatch{{Entry: WatchEntry{Name: "Taylor Swift", MinimumSimilarity: 1}, Similarity: 1}}, matches)
+
+	// Empty entry name matches any listing
+	watchlist = NewWatchlist(WatchEntry{})
+	matches = watchlist.Match(listing)
+	require.Equal(t, []WatchMatch{{Similarity: 1}}, matches)
+}
+
+func TestWatchlistMatchesShortNames(t *testing.T) {
+	watchlist := NewWatchlist(
+		WatchEntry{Name: "Blur", MinimumSimilarity: 0.7},
+		WatchEntry{Name: "Muse", MinimumSimilarity: 0.7},
+	)
+
+	// Transposed letters share no trigrams with the entry names, but are similar enough to match
+	matches := watchlist.Match(twigots.TicketListing{Event: twigots.Event{Name: "Bulr"}})
+	require.Equal(t, []WatchMatch{{Entry: WatchEntry{Name: "Blur", MinimumSimilarity: 0.7}, Similarity: 0.75}}, matches)
+
+	matches = watchlist.Match(twigots.TicketListing{Event: twigots.Event{Name: "Msue"}})
+	require.Equal(t, []WatchMatch{{Entry: WatchEntry{Name: "Muse", MinimumSimilarity: 0.7}, Similarity: 0.75}}, matches)
+}
+
+func TestWatchlistMatchesAliases(t *testing.T) {
+	watchlist := NewWatchlist(
+		WatchEntry{Name: "MCR", MinimumSimilarity: 0.9},
+		WatchEntry{Name: "Les Misérables", MinimumSimilarity: 0.9},
+	)
+
+	// Listings matching an alias of an entry name should match, even if they share no trigrams with the entry name
+	matches := watchlist.Match(twigots.TicketListing{Event: twigots.Event{Name: "My Chemical Romance"}})
+	require.Equal(t, []WatchMatch{{Entry: WatchEntry{Name: "MCR", MinimumSimilarity: 0.9}, Similarity: 1}}, matches)
+
+	matches = watchlist.Match(twigots.TicketListing{Event: twigots.Event{Name: "Les Miz - Sondheim Theatre"}})
+	require.Len(t, matches, 1)
+	require.Equal(t, "Les Misérables", matches[0].Entry.Name)
+	require.InDelta(t, 1, matches[0].Similarity, 0.001)
+}
+
+func TestWatchlistWithoutAliases(t *testing.T) {
+	entries := []WatchEntry{{Name: "MCR", MinimumSimilarity: 0.9}}
+	listing := twigots.TicketListing{Event: twigots.Event{Name: "My Chemical Romance"}}
+
+	watchlist := NewWatchlistWithOptions(entries, WatchlistOptions{})
+	require.Empty(t, watchlist.Match(listing))
+	require.False(t, EventNameWithOptions("MCR", EventNameOptions{MinimumSimilarity: 0.9})(listing))
+
+	watchlist = NewWatchlistWithOptions(entries, WatchlistOptions{Aliases: DefaultAliases()})
+	require.Len(t, watchlist.Match(listing), 1)
+}
+
+func FuzzWatchlistMatchesEventName(f *testing.F) {
+	for _, minimumSimilarity := range []float64{0.5, 0.7, 0.75, 0.8, 0.9} {
+		f.Add("Blur", "Bulr", minimumSimilarity)
+		f.Add("Muse", "Msue", minimumSimilarity)
+		f.Add("Oasis", "Oasis Live", minimumSimilarity)
+		f.Add("The Who", "Teh Woh", minimumSimilarity)
+		f.Add("Taylor Swift", "Talyor Siwft | The Eras Tuor", minimumSimilarity)
+		f.Add("Arctic Monkeys", "Artcic Mnokeys", minimumSimilarity)
+		f.Add("Dua Lipa", "Dau Lpia", minimumSimilarity)
+		f.Add("MCR", "My Chemical Romance | Live", minimumSimilarity)
+		f.Add("Les Mis", "Les Misreables", minimumSimilarity)
+	}
+
+	f.Fuzz(func(t *testing.T, entryName, listingEventName string, minimumSimilarity float64) {
+		entry := WatchEntry{Name: entryName, MinimumSimilarity: minimumSimilarity}
+		listing := twigots.TicketListing{Event: twigots.Event{Name: listingEventName}}
+
+		expected := EventName(entry.Name, entry.MinimumSimilarity)(listing)
+		require.Equal(
+			t, expected, NewWatchlist(entry).Matches(listing),
+			"match of %q to %q with similarity %v", listingEventName, entryName, minimumSimilarity,
+		)
+	})
+}
+
+func BenchmarkWatchlist(b *testing.B) {
+	watchedEventNames := benchmarkWatchedEventNames()
+	entries := make([]WatchEntry, 0, len(watchedEventNames))
+	for _, watchedEventName := range watchedEventNames {
+		entries = append(entries, WatchEntry{Name: watchedEventName})
+	}
+	benchmarkPredicates(b, []TicketListingPredicate{NewWatchlist(entries...).Matches})
+}
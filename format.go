@@ -0,0 +1,314 @@
+package twigots
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/language"
+)
+
+// priceFormat is the convention used to format prices in a locale.
+type priceFormat struct {
+	// decimalSeparator separates the major and minor units e.g. "." in 1,234.50
+	decimalSeparator string
+	// groupSeparator separates groups of thousands e.g. "," in 1,234.50
+	groupSeparator string
+	// symbolAfter sets whether the currency symbol (or code) goes after the amount e.g. 12,00 €
+	symbolAfter bool
+	// symbolSpace sets whether there is a space between the currency symbol and the amount e.g. € 12,00
+	symbolSpace bool
+}
+
+const (
+	// Non-breaking space, used as a group separator and to separate symbols from amounts
+	nbsp = "\u00a0"
+	// Narrow non-breaking space, used as a group separator in French
+	narrowNbsp = "\u202f"
+)
+
+// defaultPriceFormat is the format used for English, and locales without a known convention.
+var defaultPriceFormat = priceFormat{decimalSeparator: ".", groupSeparator: ","}
+
+// priceFormats are the conventions used to format prices, keyed by locale.
+// Locales with a region (e.g. de-CH) take precedence over locales without one (e.g. de).
+var priceFormats = map[string]priceFormat{
+	"en":    defaultPriceFormat,
+	"ja":    defaultPriceFormat,
+	"de":    {decimalSeparator: ",", groupSeparator: ".", symbolAfter: true, symbolSpace: true},
+	"de-AT": {decimalSeparator: ",", groupSeparator: nbsp, symbolSpace: true},
+	"de-CH": {decimalSeparator: ".", groupSeparator: "’", symbolSpace: true},
+	"fr":    {decimalSeparator: ",", groupSeparator: narrowNbsp, symbolAfter: true, symbolSpace: true},
+	"fr-CH": {decimalSeparator: ",", groupSeparator: narrowNbsp, symbolAfter: true, symbolSpace: true},
+	"it":    {decimalSeparator: ",", groupSeparator: ".", symbolAfter: true, symbolSpace: true},
+	"it-CH": {decimalSeparator: ".", groupSeparator: "’", symbolSpace: true},
+	"es":    {decimalSeparator: ",", groupSeparator: ".", symbolAfter: true, symbolSpace: true},
+	"pt":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"nl":    {decimalSeparator: ",", groupSeparator: ".", symbolSpace: true},
+	"da":    {decimalSeparator: ",", groupSeparator: ".", symbolAfter: true, symbolSpace: true},
+	"sv":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"nb":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"fi":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"pl":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"cs":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"hu":    {decimalSeparator: ",", groupSeparator: nbsp, symbolAfter: true, symbolSpace: true},
+	"is":    {decimalSeparator: ",", groupSeparator: ".", symbolAfter: true, symbolSpace: true},
+}
+
+// Format formats the price using the conventions of a locale (e.g. language.German or language.MustParse("fr-CH"))
+// for the decimal separator, thousands separator and the position of the currency symbol.
+// e.g. £1,234.50 in English, 1.234,50 € in German, and 1 234,50 € in French.
+//
+// Currencies without a symbol use their currency code instead e.g. 1 234,50 SEK.
+// Locales without a known convention are formatted like English.
+func (p Price) Format(locale language.Tag) string {
+	format := localePriceFormat(locale)
+
+	currencyString := p.Currency.Symbol()
+	symbolSpace := format.symbolSpace
+	if currencyString == "" {
+		// Always separate codes from amounts
+		currencyString = p.Currency.Value
+		symbolSpace = true
+	}
+
+	amountString := formatAmount(absInt(p.Amount), p.Currency.Exponent())
+	amountString = groupAmount(amountString, format.decimalSeparator, format.groupSeparator)
+
+	separator := ""
+	if symbolSpace {
+		separator = nbsp
+	}
+
+	sign := ""
+	if p.Amount < 0 {
+		sign = "-"
+	}
+
+	// Codes without a locale symbol position go after the amount, as in Price.String
+	if format.symbolAfter || (p.Currency.Symbol() == "" && format == defaultPriceFormat) {
+		return sign + amountString + separator + currencyString
+	}
+	return sign + currencyString + separator + amountString
+}
+
+// localePriceFormat gets the price format convention of a locale.
+func localePriceFormat(locale language.Tag) priceFormat {
+	base, _ := locale.Base()
+	region, confidence := locale.Region()
+	if confidence == language.Exact {
+		format, ok := priceFormats[base.String()+"-"+region.String()]
+		if ok {
+			return format
+		}
+	}
+
+	format, ok := priceFormats[base.String()]
+	if ok {
+		return format
+	}
+
+	return defaultPriceFormat
+}
+
+// groupAmount replaces the decimal point of a formatted amount (see formatAmount) with a decimal separator,
+// and inserts a group separator between every group of thousands.
+func groupAmount(amount, decimalSeparator, groupSeparator string) string {
+	integerPart, fractionalPart, hasFraction := strings.Cut(amount, ".")
+
+	var builder strings.Builder
+	for idx := 0; idx < len(integerPart); idx++ {
+		if idx > 0 && (len(integerPart)-idx)%3 == 0 {
+			builder.WriteString(groupSeparator)
+		}
+		builder.WriteByte(integerPart[idx])
+	}
+
+	if hasFraction {
+		builder.WriteString(decimalSeparator)
+		builder.WriteString(fractionalPart)
+	}
+
+	return builder.String()
+}
+
+// ParsePrice parses a price from a string, such as one entered by a user.
+// The string must contain a currency symbol (e.g. £, €, $ or ¥) or code (e.g. GBP), either before or after
+// the amount, and can contain a leading minus sign.
+//
+// Both "." and "," are accepted as decimal separators. A separator is treated as a decimal separator if it is
+// the last separator and is followed by no more digits than the number of decimal places of the currency
+// (see Currency.Exponent). All other separators, along with spaces and apostrophes, are treated as thousands
+// separators, and must separate groups of exactly 3 digits.
+//
+// e.g. "£1,234.50", "1.234,50 €", "€ 12", "12 EUR", "CHF 1'234.50" and "¥1,000" are all valid.
+func ParsePrice(s string) (Price, error) {
+	priceString := strings.TrimSpace(s)
+	if priceString == "" {
+		return Price{}, errors.New("price is empty")
+	}
+
+	negative := false
+	if strings.HasPrefix(priceString, "-") {
+		negative = true
+		priceString = strings.TrimSpace(priceString[1:])
+	}
+
+	currency, amountString, err := cutCurrency(priceString)
+	if err != nil {
+		return Price{}, fmt.Errorf("price '%s' is not valid: %w", s, err)
+	}
+
+	// Allow the minus sign to come after a symbol e.g. £-1.50
+	if !negative && strings.HasPrefix(amountString, "-") {
+		negative = true
+		amountString = strings.TrimSpace(amountString[1:])
+	}
+
+	amount, err := parseAmount(amountString, currency.Exponent())
+	if err != nil {
+		return Price{}, fmt.Errorf("price '%s' is not valid: %w", s, err)
+	}
+
+	if negative {
+		amount = -amount
+	}
+
+	return Price{
+		Currency: currency,
+		Amount:   amount,
+	}, nil
+}
+
+// cutCurrency cuts the currency symbol or code from the start or end of a price string,
+// returning the currency and the remaining amount string.
+func cutCurrency(priceString string) (Currency, string, error) {
+	// Try symbols
+	for _, currency := range Currencies.Members() {
+		symbol := currency.Symbol()
+		if symbol == "" {
+			continue
+		}
+
+		amountString, ok := strings.CutPrefix(priceString, symbol)
+		if !ok {
+			amountString, ok = strings.CutSuffix(priceString, symbol)
+		}
+		if ok {
+			return currency, trimSpace(amountString), nil
+		}
+	}
+
+	// Try codes
+	startIdx := strings.IndexFunc(priceString, isAmountRune)
+	endIdx := strings.LastIndexFunc(priceString, isAmountRune)
+	if startIdx == -1 {
+		return Currency{}, "", errors.New("no amount")
+	}
+
+	var code, amountString string
+	switch {
+	case startIdx > 0 && endIdx < len(priceString)-1:
+		return Currency{}, "", errors.New("currency must be before or after the amount")
+	case startIdx > 0:
+		code = priceString[:startIdx]
+		amountString = priceString[startIdx:]
+	case endIdx < len(priceString)-1:
+		code = priceString[endIdx+1:]
+		amountString = priceString[:endIdx+1]
+	default:
+		return Currency{}, "", errors.New("no currency")
+	}
+
+	code = strings.ToUpper(trimSpace(code))
+	currency := Currencies.Parse(code)
+	if currency == nil {
+		return Currency{}, "", fmt.Errorf("currency '%s' is not valid", code)
+	}
+
+	return *currency, trimSpace(amountString), nil
+}
+
+// parseAmount parses an amount string (without a currency) into an amount in minor units of a currency with the
+// exponent specified.
+func parseAmount(amountString string, exponent int) (int, error) {
+	if amountString == "" {
+		return 0, errors.New("no amount")
+	}
+
+	// Find the decimal separator
+	integerPart := amountString
+	fractionalPart := ""
+	decimalIdx := strings.LastIndexAny(amountString, ".,")
+	if decimalIdx != -1 {
+		digitsAfter := amountString[decimalIdx+1:]
+		// Whether the separator appears more than once e.g. 1,234,567
+		repeatedSeparator := strings.Contains(amountString[:decimalIdx], amountString[decimalIdx:decimalIdx+1])
+		// Whether there is a different separator before the separator e.g. 1,234.50
+		differentSeparator := strings.ContainsAny(amountString[:decimalIdx], ".,") && !repeatedSeparator
+		switch {
+		case differentSeparator:
+			// The last of two different separators is always a decimal separator e.g. 1,234.50
+			if len(digitsAfter) > exponent {
+				return 0, fmt.Errorf("amount has more than %d decimal places", exponent)
+			}
+			integerPart, fractionalPart = amountString[:decimalIdx], digitsAfter
+		case !repeatedSeparator && len(digitsAfter) > 0 && len(digitsAfter) <= exponent:
+			integerPart, fractionalPart = amountString[:decimalIdx], digitsAfter
+		}
+	}
+
+	if !isDigits(fractionalPart) {
+		return 0, fmt.Errorf("amount '%s' is not a number", amountString)
+	}
+
+	// Remove group separators, checking groups are the correct size
+	groups := strings.FieldsFunc(integerPart, isGroupSeparator)
+	if len(groups) == 0 {
+		return 0, fmt.Errorf("amount '%s' is not a number", amountString)
+	}
+	for idx := 0; idx < len(groups); idx++ {
+		group := groups[idx]
+		if !isDigits(group) || group == "" {
+			return 0, fmt.Errorf("amount '%s' is not a number", amountString)
+		}
+		if idx > 0 && len(group) != 3 {
+			return 0, fmt.Errorf("amount '%s' has invalid thousands separators", amountString)
+		}
+	}
+
+	// Pad the fractional part to the exponent, so the amount is in minor units
+	digits := strings.Join(groups, "") + fractionalPart + strings.Repeat("0", exponent-len(fractionalPart))
+	amount, err := strconv.Atoi(digits)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse amount: %w", err)
+	}
+
+	return amount, nil
+}
+
+func isAmountRune(r rune) bool {
+	return (r >= '0' && r <= '9') || r == '.' || r == ','
+}
+
+func isGroupSeparator(r rune) bool {
+	return r == '.' || r == ',' || r == '\'' || r == '’' || unicode.IsSpace(r) || r == ' '
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// trimSpace trims whitespace, including non-breaking spaces, from a string.
+func trimSpace(s string) string {
+	return strings.TrimFunc(s, func(r rune) bool {
+		return unicode.IsSpace(r) || r == ' '
+	})
+}
@@ -0,0 +1,112 @@
+package twigots
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/text/language"
+)
+
+func TestPriceFormat(t *testing.T) {
+	tests := []struct {
+		price    Price
+		locale   language.Tag
+		expected string
+	}{
+		{Price{Currency: CurrencyGBP, Amount: 123450}, language.English, "£1,234.50"},
+		{Price{Currency: CurrencyGBP, Amount: 123450}, language.BritishEnglish, "£1,234.50"},
+		{Price{Currency: CurrencyGBP, Amount: 5}, language.English, "£0.05"},
+		{Price{Currency: CurrencyGBP, Amount: -150}, language.English, "-£1.50"},
+		{Price{Currency: CurrencyEUR, Amount: 1200}, language.German, "12,00 €"},
+		{Price{Currency: CurrencyEUR, Amount: 123456789}, language.German, "1.234.567,89 €"},
+		{Price{Currency: CurrencyEUR, Amount: 123450}, language.French, "1 234,50 €"},
+		{Price{Currency: CurrencyEUR, Amount: 123450}, language.Dutch, "€ 1.234,50"},
+		{Price{Currency: CurrencyEUR, Amount: -1200}, language.German, "-12,00 €"},
+		{Price{Currency: CurrencyCHF, Amount: 123450}, language.MustParse("de-CH"), "CHF 1’234.50"},
+		{Price{Currency: CurrencyCHF, Amount: 9995}, language.English, "99.95 CHF"},
+		{Price{Currency: CurrencySEK, Amount: 123450}, language.Swedish, "1 234,50 SEK"},
+		{Price{Currency: CurrencyJPY, Amount: 1200}, language.Japanese, "¥1,200"},
+		{Price{Currency: CurrencyUSD, Amount: 100000}, language.Und, "$1,000.00"},
+		{Price{Currency: CurrencyUSD, Amount: 100}, language.Korean, "$1.00"}, // Unknown locale
+	}
+	for _, test := range tests {
+		t.Run(test.locale.String()+"/"+test.expected, func(t *testing.T) {
+			// Compare with regular spaces for readability
+			formatted := strings.NewReplacer(nbsp, " ", narrowNbsp, " ").Replace(test.price.Format(test.locale))
+			require.Equal(t, test.expected, formatted)
+		})
+	}
+}
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		price    string
+		expected Price
+	}{
+		{"£1,234.50", Price{Currency: CurrencyGBP, Amount: 123450}},
+		{"£12", Price{Currency: CurrencyGBP, Amount: 1200}},
+		{"£12.5", Price{Currency: CurrencyGBP, Amount: 1250}},
+		{" £ 0.05 ", Price{Currency: CurrencyGBP, Amount: 5}},
+		{"-£1.50", Price{Currency: CurrencyGBP, Amount: -150}},
+		{"£-1.50", Price{Currency: CurrencyGBP, Amount: -150}},
+		{"1.234,50 €", Price{Currency: CurrencyEUR, Amount: 123450}},
+		{"1.234 €", Price{Currency: CurrencyEUR, Amount: 123400}},
+		{"12,00€", Price{Currency: CurrencyEUR, Amount: 1200}},
+		{"€ 12", Price{Currency: CurrencyEUR, Amount: 1200}},
+		{"1 234,50 €", Price{Currency: CurrencyEUR, Amount: 123450}},
+		{"$1,000,000", Price{Currency: CurrencyUSD, Amount: 100000000}},
+		{"12 EUR", Price{Currency: CurrencyEUR, Amount: 1200}},
+		{"gbp 12.50", Price{Currency: CurrencyGBP, Amount: 1250}},
+		{"CHF 1'234.50", Price{Currency: CurrencyCHF, Amount: 123450}},
+		{"CHF 1’234.50", Price{Currency: CurrencyCHF, Amount: 123450}},
+		{"¥1,000", Price{Currency: CurrencyJPY, Amount: 1000}},
+		{"1.000 ISK", Price{Currency: CurrencyISK, Amount: 1000}},
+	}
+	for _, test := range tests {
+		t.Run(test.price, func(t *testing.T) {
+			price, err := ParsePrice(test.price)
+			require.NoError(t, err)
+			require.Equal(t, test.expected, price)
+		})
+	}
+}
+
+func TestParsePriceInvalid(t *testing.T) {
+	invalidPrices := []string{
+		"",
+		"12",         // No currency
+		"£",          // No amount
+		"XXX 12",     // Invalid currency
+		"GBP 12 GBP", // Currency on both sides
+		"£12.345.6",  // Invalid groups
+		"£1,23,456",  // Invalid groups
+		"£1.234,567", // Too many decimal places
+		"£12a",       // Not a number
+		"¥12.5",      // JPY has no minor unit
+	}
+	for _, price := range invalidPrices {
+		t.Run(price, func(t *testing.T) {
+			_, err := ParsePrice(price)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestParsePriceFormat(t *testing.T) {
+	// Formatted prices should parse back to the same price
+	locales := []language.Tag{language.English, language.German, language.French, language.MustParse("de-CH")}
+	prices := []Price{
+		{Currency: CurrencyGBP, Amount: 123456789},
+		{Currency: CurrencyEUR, Amount: -5},
+		{Currency: CurrencyCHF, Amount: 100000},
+		{Currency: CurrencyJPY, Amount: 1234567},
+	}
+	for _, locale := range locales {
+		for _, price := range prices {
+			parsedPrice, err := ParsePrice(price.Format(locale))
+			require.NoError(t, err)
+			require.Equal(t, price, parsedPrice, price.Format(locale))
+		}
+	}
+}
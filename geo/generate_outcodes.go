@@ -0,0 +1,222 @@
+//go:build ignore
+
+// Generates outcodes.csv, the built-in outcode table, from the ONS Postcode Directory (ONSPD).
+//
+// The ONSPD can be downloaded from the ONS Open Geography Portal. Usage:
+//
+//	go run generate_outcodes.go -onspd ONSPD.csv -output outcodes.csv
+//
+// The table contains the centroid of every postcode district (outcode), calculated as the mean coordinates of the
+// live postcodes in the district, and the centroid of every postcode area, calculated as the mean coordinates of the
+// districts in the area. Northern Ireland postcodes (BT) are licenced separately, so their districts are not included,
+// and the coordinates of the BT area are kept from the existing table.
+//
+// Contains OS data © Crown copyright and database right.
+// Contains Royal Mail data © Royal Mail copyright and database right.
+// Source: Office for National Statistics licensed under the Open Government Licence v3.0.
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// northernIrelandArea is the postcode area of Northern Ireland.
+const northernIrelandArea = "BT"
+
+// ONSPD uses this latitude for postcodes without a grid reference.
+const noGridReferenceLatitude = 99.999999
+
+var outcodeRegex = regexp.MustCompile(`^([A-Z]{1,2})[0-9][A-Z0-9]?$`)
+
+// centroid accumulates coordinates to calculate their mean.
+type centroid struct {
+	latitude  float64
+	longitude float64
+	count     int
+}
+
+func (c *centroid) add(latitude, longitude float64) {
+	c.latitude += latitude
+	c.longitude += longitude
+	c.count++
+}
+
+func (c centroid) mean() (float64, float64) {
+	return c.latitude / float64(c.count), c.longitude / float64(c.count)
+}
+
+func main() {
+	onspdPath := flag.String("onspd", "", "path to the ONS Postcode Directory CSV")
+	outputPath := flag.String("output", "outcodes.csv", "path to write the outcode table to")
+	flag.Parse()
+
+	if *onspdPath == "" {
+		log.Fatal("path to the ONS Postcode Directory CSV must be set with -onspd")
+	}
+
+	err := generate(*onspdPath, *outputPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
+
+func generate(onspdPath, outputPath string) error {
+	districts, err := readDistricts(onspdPath)
+	if err != nil {
+		return err
+	}
+
+	// Keep the coordinates of the Northern Ireland area from the existing table
+	northernIreland, err := readArea(outputPath, northernIrelandArea)
+	if err != nil {
+		return err
+	}
+
+	areas := make(map[string]*centroid)
+	for outcode, district := range districts {
+		area := outcodeRegex.FindStringSubmatch(outcode)[1]
+		if areas[area] == nil {
+			areas[area] = &centroid{}
+		}
+		areas[area].add(district.mean())
+	}
+
+	rows := make([][]string, 0, len(areas)+len(districts)+1)
+	for area, areaCentroid := range areas {
+		rows = append(rows, centroidRow(area, *areaCentroid))
+	}
+	for outcode, district := range districts {
+		rows = append(rows, centroidRow(outcode, *district))
+	}
+	if northernIreland != nil {
+		rows = append(rows, northernIreland)
+	}
+	slices.SortFunc(rows, func(a, b []string) int { return strings.Compare(a[0], b[0]) })
+
+	file, err := os.Create(outputPath)
+	if err != nil {
+		return fmt.Errorf("failed to create outcodes file: %w", err)
+	}
+	defer file.Close()
+
+	writer := csv.NewWriter(file)
+	err = writer.Write([]string{"outcode", "latitude", "longitude"})
+	if err != nil {
+		return fmt.Errorf("failed to write outcodes: %w", err)
+	}
+	err = writer.WriteAll(rows)
+	if err != nil {
+		return fmt.Errorf("failed to write outcodes: %w", err)
+	}
+
+	return nil
+}
+
+// readDistricts reads the centroids of the postcode districts in the ONS Postcode Directory, excluding
+// terminated postcodes, postcodes without a grid reference and Northern Ireland postcodes.
+func readDistricts(onspdPath string) (map[string]*centroid, error) {
+	file, err := os.Open(onspdPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ONS Postcode Directory: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ONS Postcode Directory header: %w", err)
+	}
+
+	columns := make(map[string]int, len(header))
+	for idx, column := range header {
+		columns[strings.ToLower(strings.TrimSpace(column))] = idx
+	}
+	for _, column := range []string{"pcds", "doterm", "lat", "long"} {
+		if _, ok := columns[column]; !ok {
+			return nil, fmt.Errorf("ONS Postcode Directory has no '%s' column", column)
+		}
+	}
+
+	districts := make(map[string]*centroid)
+	for {
+		row, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read ONS Postcode Directory: %w", err)
+		}
+
+		if row[columns["doterm"]] != "" {
+			continue
+		}
+
+		outcode, _, ok := strings.Cut(row[columns["pcds"]], " ")
+		if !ok || !outcodeRegex.MatchString(outcode) || strings.HasPrefix(outcode, northernIrelandArea) {
+			continue
+		}
+
+		latitude, err := strconv.ParseFloat(row[columns["lat"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse latitude of postcode '%s': %w", row[columns["pcds"]], err)
+		}
+		longitude, err := strconv.ParseFloat(row[columns["long"]], 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse longitude of postcode '%s': %w", row[columns["pcds"]], err)
+		}
+		if latitude == noGridReferenceLatitude {
+			continue
+		}
+
+		if districts[outcode] == nil {
+			districts[outcode] = &centroid{}
+		}
+		districts[outcode].add(latitude, longitude)
+	}
+
+	return districts, nil
+}
+
+// readArea reads the row of a postcode area from an existing outcode table.
+// If the table or the area does not exist, nil is returned.
+func readArea(path, area string) ([]string, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outcodes file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := csv.NewReader(file).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outcodes: %w", err)
+	}
+
+	for _, row := range rows {
+		if row[0] == area {
+			return row, nil
+		}
+	}
+	return nil, nil
+}
+
+func centroidRow(outcode string, outcodeCentroid centroid) []string {
+	latitude, longitude := outcodeCentroid.mean()
+	return []string{
+		outcode,
+		strconv.FormatFloat(latitude, 'f', 4, 64),
+		strconv.FormatFloat(longitude, 'f', 4, 64),
+	}
+}
@@ -0,0 +1,48 @@
+// Package geo provides geographic coordinates, distances and the resolution of UK postcodes to coordinates.
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// EarthRadiusKm is the mean radius of the earth in kilometres.
+const EarthRadiusKm = 6371.0
+
+// Coordinates are the latitude and longitude of a point, in degrees.
+type Coordinates struct {
+	Latitude  float64
+	Longitude float64
+}
+
+// DistanceTo calculates the great-circle distance to other coordinates in kilometres,
+// using the haversine formula.
+// See: https://en.wikipedia.org/wiki/Haversine_formula
+func (c Coordinates) DistanceTo(other Coordinates) float64 {
+	latitude1 := degreesToRadians(c.Latitude)
+	latitude2 := degreesToRadians(other.Latitude)
+	latitudeDifference := latitude2 - latitude1
+	longitudeDifference := degreesToRadians(other.Longitude - c.Longitude)
+
+	haversine := math.Pow(math.Sin(latitudeDifference/2), 2) +
+		math.Cos(latitude1)*math.Cos(latitude2)*math.Pow(math.Sin(longitudeDifference/2), 2)
+
+	// Clamp to prevent NaN from floating point errors for antipodal points
+	haversine = math.Min(1, haversine)
+
+	return 2 * EarthRadiusKm * math.Asin(math.Sqrt(haversine))
+}
+
+// String formats the coordinates as a string with 4 decimal places e.g. 51.5074,-0.1278
+func (c Coordinates) String() string {
+	return fmt.Sprintf(
+		"%s,%s",
+		strconv.FormatFloat(c.Latitude, 'f', 4, 64),
+		strconv.FormatFloat(c.Longitude, 'f', 4, 64),
+	)
+}
+
+func degreesToRadians(degrees float64) float64 {
+	return degrees * math.Pi / 180
+}
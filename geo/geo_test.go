@@ -0,0 +1,26 @@
+package geo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDistanceTo(t *testing.T) {
+	london := Coordinates{Latitude: 51.5074, Longitude: -0.1278}
+	manchester := Coordinates{Latitude: 53.4808, Longitude: -2.2426}
+	paris := Coordinates{Latitude: 48.8566, Longitude: 2.3522}
+
+	require.InDelta(t, 262, london.DistanceTo(manchester), 2)
+	require.InDelta(t, 344, london.DistanceTo(paris), 2)
+	require.InDelta(t, london.DistanceTo(paris), paris.DistanceTo(london), 0.001)
+	require.Zero(t, london.DistanceTo(london))
+
+	// Antipodal points should be half the circumference of the earth apart
+	antipode := Coordinates{Latitude: -51.5074, Longitude: 179.8722}
+	require.InDelta(t, 20015, london.DistanceTo(antipode), 1)
+}
+
+func TestCoordinatesString(t *testing.T) {
+	require.Equal(t, "51.5074,-0.1278", Coordinates{Latitude: 51.50741, Longitude: -0.12781}.String())
+}
@@ -0,0 +1,194 @@
+package geo
+
+import (
+	_ "embed"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+//go:embed outcodes.csv
+var defaultOutcodesCSV string
+
+// ErrUnknownPostcode is returned when the coordinates of a postcode are not known.
+var ErrUnknownPostcode = errors.New("unknown postcode")
+
+var (
+	// Default outcodes, parsed on first use
+	defaultOutcodes     *Outcodes
+	defaultOutcodesOnce sync.Once
+
+	// Regular expression to match a full UK postcode, capturing the outward code (outcode) e.g. SW1A in SW1A 1AA
+	postcodeRegex = regexp.MustCompile(`^([A-Z]{1,2}[0-9][A-Z0-9]?)[0-9][A-Z]{2}$`)
+	// Regular expression to match a UK outward code (outcode) e.g. SW1A, capturing the postcode area e.g. SW
+	outcodeRegex = regexp.MustCompile(`^([A-Z]{1,2})[0-9][A-Z0-9]?$`)
+	// Regular expression to match a UK postcode area e.g. SW
+	postcodeAreaRegex = regexp.MustCompile(`^[A-Z]{1,2}$`)
+)
+
+// Outcodes is a table of the coordinates of the centroids of UK postcode districts (outcodes) e.g. SW1A or M1,
+// and postcode areas e.g. SW or M.
+//
+// Postcodes are resolved to the coordinates of their district if known, falling back to the coordinates of their
+// area. The accuracy of resolved coordinates therefore depends on whether the table contains districts, or only
+// areas (in which case accuracy can be tens of kilometres).
+type Outcodes struct {
+	coordinates map[string]Coordinates
+}
+
+// NewOutcodes creates an outcode table from a map of outcodes (or postcode areas) to their coordinates.
+// Outcodes are not case sensitive, and can contain spaces.
+func NewOutcodes(coordinates map[string]Coordinates) (*Outcodes, error) {
+	outcodes := &Outcodes{coordinates: make(map[string]Coordinates, len(coordinates))}
+	for outcode, outcodeCoordinates := range coordinates {
+		normalisedOutcode := normalisePostcode(outcode)
+		if !outcodeRegex.MatchString(normalisedOutcode) && !postcodeAreaRegex.MatchString(normalisedOutcode) {
+			return nil, fmt.Errorf("outcode '%s' is not valid", outcode)
+		}
+		outcodes.coordinates[normalisedOutcode] = outcodeCoordinates
+	}
+	return outcodes, nil
+}
+
+// ParseOutcodes parses an outcode table from CSV.
+// CSV should have a header row, followed by rows of an outcode (or postcode area), latitude and longitude
+// e.g.
+//
+//	outcode,latitude,longitude
+//	SW1A,51.501,-0.141
+//	M,53.480,-2.240
+func ParseOutcodes(r io.Reader) (*Outcodes, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = 3
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read outcodes: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("outcodes has no header row")
+	}
+
+	coordinates := make(map[string]Coordinates, len(rows)-1)
+	for idx := 1; idx < len(rows); idx++ {
+		row := rows[idx]
+
+		latitude, err := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse latitude of outcode '%s': %w", row[0], err)
+		}
+		longitude, err := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse longitude of outcode '%s': %w", row[0], err)
+		}
+
+		coordinates[row[0]] = Coordinates{Latitude: latitude, Longitude: longitude}
+	}
+
+	return NewOutcodes(coordinates)
+}
+
+// LoadOutcodes loads an outcode table from a CSV file. See ParseOutcodes for the file format.
+//
+// Use this to load a more complete or accurate table than the default, such as one derived from
+// the ONS Postcode Directory or Ordnance Survey Code-Point Open.
+func LoadOutcodes(path string) (*Outcodes, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open outcodes file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseOutcodes(file)
+}
+
+// DefaultOutcodes returns the built-in outcode table.
+//
+// The built-in table only contains the approximate coordinates of the centre of each UK postcode area
+// (e.g. SW or M), including Northern Ireland, the Channel Islands and the Isle of Man.
+// It does not contain individual districts, so all postcodes in an area resolve to the same coordinates.
+// This is sufficient for coarse distance filtering (e.g. within 50km), but use LoadOutcodes
+// with a district level table if more accuracy is needed.
+//
+// A district level table can be generated from the ONS Postcode Directory with generate_outcodes.go
+// (see task generate:outcodes). Generated tables contain OS data © Crown copyright and database right,
+// and Royal Mail data © Royal Mail copyright and database right, and are licensed under the
+// Open Government Licence v3.0 (source: Office for National Statistics).
+func DefaultOutcodes() *Outcodes {
+	defaultOutcodesOnce.Do(func() {
+		outcodes, err := ParseOutcodes(strings.NewReader(defaultOutcodesCSV))
+		if err != nil {
+			// An error will never occur if the embedded outcodes are valid.
+			// If an error does occur (due to an error in the outcodes file), panic so we catch it.
+			panic(err)
+		}
+		defaultOutcodes = outcodes
+	})
+	return defaultOutcodes
+}
+
+// Len returns the number of outcodes (and postcode areas) in the table.
+func (o *Outcodes) Len() int {
+	return len(o.coordinates)
+}
+
+// Coordinates gets the coordinates of a UK postcode (e.g. SW1A 1AA) or outcode (e.g. SW1A).
+// The postcode is not case sensitive, and spaces are ignored.
+//
+// If the postcode is not valid, or neither its district nor area are in the table, an error wrapping
+// ErrUnknownPostcode is returned.
+func (o *Outcodes) Coordinates(postcode string) (Coordinates, error) {
+	outcode, err := Outcode(postcode)
+	if err != nil {
+		return Coordinates{}, err
+	}
+
+	coordinates, ok := o.coordinates[outcode]
+	if ok {
+		return coordinates, nil
+	}
+
+	area := outcodeRegex.FindStringSubmatch(outcode)[1]
+	coordinates, ok = o.coordinates[area]
+	if ok {
+		return coordinates, nil
+	}
+
+	return Coordinates{}, fmt.Errorf("%w '%s'", ErrUnknownPostcode, postcode)
+}
+
+// PostcodeCoordinates gets the coordinates of a UK postcode (e.g. SW1A 1AA) or outcode (e.g. SW1A)
+// using the default outcode table. See DefaultOutcodes and Outcodes.Coordinates.
+func PostcodeCoordinates(postcode string) (Coordinates, error) {
+	return DefaultOutcodes().Coordinates(postcode)
+}
+
+// Outcode gets the outward code (outcode) of a UK postcode e.g. SW1A for SW1A 1AA.
+// If the postcode is already an outcode, it is returned normalised.
+//
+// If the postcode is not valid, an error wrapping ErrUnknownPostcode is returned.
+func Outcode(postcode string) (string, error) {
+	normalisedPostcode := normalisePostcode(postcode)
+
+	match := postcodeRegex.FindStringSubmatch(normalisedPostcode)
+	if match != nil {
+		return match[1], nil
+	}
+
+	if outcodeRegex.MatchString(normalisedPostcode) {
+		return normalisedPostcode, nil
+	}
+
+	return "", fmt.Errorf("%w '%s': not a valid UK postcode", ErrUnknownPostcode, postcode)
+}
+
+// normalisePostcode normalises a postcode by converting it to upper case and removing all whitespace.
+func normalisePostcode(postcode string) string {
+	return strings.Join(strings.Fields(strings.ToUpper(postcode)), "")
+}
@@ -0,0 +1,80 @@
+package geo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestOutcode(t *testing.T) {
+	tests := map[string]string{
+		"SW1A 1AA": "SW1A",
+		"sw1a1aa":  "SW1A",
+		"E20 2ST":  "E20",
+		"M1 1AE":   "M1",
+		"N1 1AA":   "N1",
+		"N11 1AA":  "N11",
+		"WC2R 0ET": "WC2R",
+		"SW1A":     "SW1A",
+		" de74 ":   "DE74",
+	}
+	for postcode, expected := range tests {
+		outcode, err := Outcode(postcode)
+		require.NoError(t, err, postcode)
+		require.Equal(t, expected, outcode, postcode)
+	}
+
+	for _, postcode := range []string{"", "SW", "D02 X285", "12345", "SW1A 1AAA"} {
+		_, err := Outcode(postcode)
+		require.ErrorIs(t, err, ErrUnknownPostcode, postcode)
+	}
+}
+
+func TestDefaultOutcodes(t *testing.T) {
+	// Every postcode area should be in the table, along with any postcode districts
+	outcodes := DefaultOutcodes()
+	require.GreaterOrEqual(t, outcodes.Len(), 124)
+
+	// Postcodes should resolve to the coordinates of their district, or their area if their district is not known
+	stratford := Coordinates{Latitude: 51.5416, Longitude: -0.0042}
+	coordinates, err := PostcodeCoordinates("E20 2ST")
+	require.NoError(t, err)
+	require.Less(t, stratford.DistanceTo(coordinates), 5.0)
+
+	// Every area should be in the UK, Channel Islands or Isle of Man
+	for _, outcodeCoordinates := range outcodes.coordinates {
+		require.InDelta(t, 55, outcodeCoordinates.Latitude, 6)
+		require.InDelta(t, -3, outcodeCoordinates.Longitude, 5)
+	}
+
+	// Resolved coordinates should be roughly in the right place
+	london := Coordinates{Latitude: 51.5074, Longitude: -0.1278}
+	coordinates, err = PostcodeCoordinates("WC2R 0ET")
+	require.NoError(t, err)
+	require.Less(t, london.DistanceTo(coordinates), 5.0)
+
+	_, err = PostcodeCoordinates("QQ1 1AA") // No QQ postcode area
+	require.ErrorIs(t, err, ErrUnknownPostcode)
+}
+
+func TestParseOutcodes(t *testing.T) {
+	outcodes, err := ParseOutcodes(strings.NewReader("outcode,latitude,longitude\nsw1a,51.501,-0.141\nSW,51.46,-0.17\n"))
+	require.NoError(t, err)
+	require.Equal(t, 2, outcodes.Len())
+
+	// Districts should be used in preference to areas
+	coordinates, err := outcodes.Coordinates("SW1A 1AA")
+	require.NoError(t, err)
+	require.Equal(t, Coordinates{Latitude: 51.501, Longitude: -0.141}, coordinates)
+
+	coordinates, err = outcodes.Coordinates("SW11 1AA")
+	require.NoError(t, err)
+	require.Equal(t, Coordinates{Latitude: 51.46, Longitude: -0.17}, coordinates)
+
+	_, err = ParseOutcodes(strings.NewReader("outcode,latitude,longitude\nSW1A,north,-0.141\n"))
+	require.Error(t, err)
+
+	_, err = ParseOutcodes(strings.NewReader("outcode,latitude,longitude\nNOT AN OUTCODE,51,-0.141\n"))
+	require.Error(t, err)
+}
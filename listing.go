@@ -15,6 +15,12 @@ type TicketListing struct {
 	// Number of tickets in the listing
 	NumTickets int `json:"ticketQuantity"`
 
+	// SplitRule determines how the tickets in the listing can be split when buying.
+	// This is not currently included in the twickets feed, so will be unset unless set manually,
+	// in which case only all tickets in the listing can be bought.
+	// Use CanBuy to check whether a number of tickets can be bought from the listing.
+	SplitRule SplitRule `json:"-"`
+
 	// TotalPriceExclFee is the total price of all tickets, excluding fee.
 	// Use TotalPriceInclFee to get the total price of all tickets, including fee.
 	// Use TicketPriceExclFee to get the price of a single ticket, excluding fee.
@@ -48,7 +54,15 @@ func (l TicketListing) URL() string {
 	return fmt.Sprintf("https://www.twickets.live/app/block/%s,%d", l.Id, l.NumTickets)
 }
 
+// CanBuy checks whether the number of tickets specified can be bought from the listing,
+// according to the listing split rule.
+func (l TicketListing) CanBuy(numTickets int) bool {
+	return l.SplitRule.CanBuy(numTickets, l.NumTickets)
+}
+
 // TicketPriceExclFee is price of a single ticket, excluding fee.
+// This is the average price of the tickets, rounded to the nearest minor unit.
+// Use FeeBreakdown to get the exact price of each ticket.
 //
 // Use TotalPriceExclFee to get the total price of all tickets, excluding fee.
 //
@@ -56,21 +70,27 @@ func (l TicketListing) URL() string {
 //
 // Use TicketPriceInclFee to get the price of a single ticket, including fee.
 func (l TicketListing) TicketPriceExclFee() Price {
-	return l.TotalPriceExclFee.Divide(l.NumTickets).Add(l.TwicketsFeePerTicket())
+	return l.TotalPriceExclFee.Divide(l.NumTickets)
 }
 
-// TotalPriceExclFee is the total price of all tickets, including fee.
+// TotalPriceInclFee is the total price of all tickets, including fee.
 //
 // Use TotalPriceExclFee to get the total price of all tickets, excluding fee.
 //
 // Use TicketPriceExclFee to get the price of a single ticket, excluding fee.
 //
 // Use TicketPriceInclFee to get the price of a single ticket, including fee.
+//
+// The price and fee are added using Price.Add. If they have different currencies (which twickets listings should
+// never have), they cannot be added, so the fee amount is added as if it were in the currency of the price excl fee.
+// Use Price.Add with TotalPriceExclFee and TwicketsFee to detect this.
 func (l TicketListing) TotalPriceInclFee() Price {
-	return l.TotalPriceExclFee.Add(l.TwicketsFee)
+	return addFee(l.TotalPriceExclFee, l.TwicketsFee)
 }
 
-// TicketPriceExclFee is price of a single ticket, including fee.
+// TicketPriceInclFee is price of a single ticket, including fee.
+// This is the average price of the tickets, rounded to the nearest minor unit.
+// Use FeeBreakdown to get the exact price of each ticket.
 //
 // Use TotalPriceExclFee to get the total price of all tickets, excluding fee.
 //
@@ -82,6 +102,8 @@ func (l TicketListing) TicketPriceInclFee() Price {
 }
 
 // TwicketsFeePerTicket is the twickets fee per ticket.
+// This is the average fee of the tickets, rounded to the nearest minor unit.
+// Use FeeBreakdown to get the exact fee of each ticket.
 //
 // Use TwicketsFee to get the total fee for all tickets.
 func (l TicketListing) TwicketsFeePerTicket() Price {
@@ -100,8 +122,17 @@ func (l TicketListing) OriginalTicketPrice() Price {
 // Discount is returned as a value between 0 and 1 (with 1 representing 100% off).
 // If ticket is being sold at its original price, the addition of the twickets fee will
 // cause discount to be < 0 i.e. the total ticket price will have gone up.
+//
+// If the original price is unknown (<=0) or in a different currency, discount will be 0.
 func (l TicketListing) Discount() float64 {
-	return (1 - l.TotalPriceInclFee().Number()/l.OriginalTotalPrice.Number())
+	price := l.TotalPriceInclFee()
+	originalPrice := l.OriginalTotalPrice
+	if price.Currency != originalPrice.Currency || originalPrice.Amount <= 0 {
+		return 0
+	}
+
+	// Calculate using the ratio of amounts, as both are in the same minor unit
+	return 1 - float64(price.Amount)/float64(originalPrice.Amount)
 }
 
 // DiscountString is the discount on the original price of a single ticket, including any fee
@@ -5,8 +5,10 @@ import (
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/geo"
 	"github.com/ahobsonsayers/utilopia/testutils"
 	"github.com/stretchr/testify/require"
 )
@@ -23,6 +25,7 @@ func TestUnmarshalFeedJson(t *testing.T) {
 	require.Equal(t, "Shame", listings[0].Event.Lineup[2].Artist.Name)
 	require.Equal(t, "London Stadium", listings[0].Event.Venue.Name)
 	require.Equal(t, "Foo Fighters - Everything Or Nothing At All Tour", listings[0].Tour.Name)
+	require.Equal(t, []twigots.Country{twigots.CountryUnitedKingdom, {Value: "US"}}, listings[0].Tour.Countries)
 	require.Equal(t, 3, listings[0].NumTickets)
 	require.Equal(t, "£180.00", listings[0].TotalPriceExclFee.String())
 	require.Equal(t, "£38.25", listings[0].TwicketsFee.String())
@@ -82,3 +85,75 @@ func testTicketListings(t *testing.T) twigots.TicketListings {
 
 	return tickets
 }
+
+func TestVenueCoordinates(t *testing.T) {
+	listings := testTicketListings(t)
+
+	coordinates, err := listings[0].Event.Venue.Coordinates() // London Stadium, E20 2ST
+	require.NoError(t, err)
+	require.Less(t, coordinates.DistanceTo(geo.Coordinates{Latitude: 51.5387, Longitude: -0.0166}), 10.0)
+
+	_, err = twigots.Venue{Name: "Unknown"}.Coordinates()
+	require.ErrorIs(t, err, geo.ErrUnknownPostcode)
+
+	_, err = twigots.Venue{Name: "Unknown", Postcode: "D02 X285"}.Coordinates()
+	require.ErrorIs(t, err, geo.ErrUnknownPostcode)
+}
+
+func TestEventStartsAt(t *testing.T) {
+	listings := testTicketListings(t)
+
+	// Event times should be in the local time zone of the venue, which is BST in summer
+	startsAt, err := listings[0].Event.StartsAt()
+	require.NoError(t, err)
+	require.Equal(t, "Europe/London", startsAt.Location().String())
+	require.Equal(t, time.Date(2024, 6, 20, 16, 0, 0, 0, time.UTC), startsAt.UTC())
+
+	// The country time zone should be used if the location has no time zone
+	event := twigots.Event{
+		Date: twigots.Date{Time: time.Date(2024, 12, 1, 0, 0, 0, 0, time.UTC)},
+		Time: twigots.Time{Time: time.Date(0, 1, 1, 20, 0, 0, 0, time.UTC)},
+		Venue: twigots.Venue{
+			Location: twigots.Location{Country: twigots.CountryGermany},
+		},
+	}
+	startsAt, err = event.StartsAt()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 12, 1, 19, 0, 0, 0, time.UTC), startsAt.UTC())
+
+	// Unknown time zones should error
+	event.Venue.Location.Country = twigots.Country{}
+	_, err = event.StartsAt()
+	require.Error(t, err)
+
+	_, err = twigots.Event{}.StartsAt()
+	require.Error(t, err)
+}
+
+func TestTourEventDays(t *testing.T) {
+	listings := testTicketListings(t)
+
+	tour := listings[1].Tour // Mean Girls, GB
+	firstEventDay, err := tour.FirstEventDay()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 6, 5, 23, 0, 0, 0, time.UTC), firstEventDay.UTC())
+
+	lastEventDay, err := tour.LastEventDay()
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2024, 10, 10, 23, 0, 0, 0, time.UTC), lastEventDay.UTC())
+
+	// Tours in countries without a known time zone should error
+	tour = listings[0].Tour // Foo Fighters, GB and US
+	_, err = tour.FirstEventDay()
+	require.Error(t, err)
+
+	// Tours in countries with different time zones should error
+	tour.Countries = []twigots.Country{twigots.CountryUnitedKingdom, twigots.CountryFrance}
+	_, err = tour.TimeZone()
+	require.Error(t, err)
+
+	// Tours in multiple countries in the same time zone should not error
+	tour.Countries = []twigots.Country{twigots.CountryUnitedKingdom, twigots.CountryUnitedKingdom}
+	_, err = tour.FirstEventDay()
+	require.NoError(t, err)
+}
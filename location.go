@@ -3,6 +3,12 @@ package twigots
 import (
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	// Embed the time zone database, so time zones can be loaded on systems without one
+	_ "time/tzdata"
 
 	"github.com/orsinium-labs/enum"
 )
@@ -11,12 +17,69 @@ var (
 	country = enum.NewBuilder[string, Country]()
 
 	CountryUnitedKingdom = country.Add(Country{"GB"})
+	CountryIreland       = country.Add(Country{"IE"})
+	CountryFrance        = country.Add(Country{"FR"})
+	CountryGermany       = country.Add(Country{"DE"})
+	CountrySpain         = country.Add(Country{"ES"})
+	CountryItaly         = country.Add(Country{"IT"})
+	CountryNetherlands   = country.Add(Country{"NL"})
+	CountryBelgium       = country.Add(Country{"BE"})
+	CountryPortugal      = country.Add(Country{"PT"})
+	CountryAustria       = country.Add(Country{"AT"})
+	CountrySwitzerland   = country.Add(Country{"CH"})
 
 	Countries = country.Enum()
 )
 
 type Country enum.Member[string]
 
+// countryTimeZones are the IANA time zones of each country.
+// Countries with multiple time zones use the time zone of their capital.
+var countryTimeZones = map[Country]string{
+	CountryUnitedKingdom: "Europe/London",
+	CountryIreland:       "Europe/Dublin",
+	CountryFrance:        "Europe/Paris",
+	CountryGermany:       "Europe/Berlin",
+	CountrySpain:         "Europe/Madrid", // Excluding the Canary Islands
+	CountryItaly:         "Europe/Rome",
+	CountryNetherlands:   "Europe/Amsterdam",
+	CountryBelgium:       "Europe/Brussels",
+	CountryPortugal:      "Europe/Lisbon", // Excluding the Azores
+	CountryAustria:       "Europe/Vienna",
+	CountrySwitzerland:   "Europe/Zurich",
+}
+
+// TimeZone gets the time zone of the country e.g. Europe/London for the United Kingdom.
+// Countries with multiple time zones (e.g. Spain and Portugal) use the time zone of their capital.
+//
+// If the country is not valid, an error is returned.
+func (c Country) TimeZone() (*time.Location, error) {
+	timeZoneName, ok := countryTimeZones[c]
+	if !ok {
+		return nil, fmt.Errorf("country '%s' has no known time zone", c.Value)
+	}
+	return time.LoadLocation(timeZoneName)
+}
+
+// Regions gets the regions of the country.
+//
+// Not all countries are split into regions, in which case an empty slice is returned.
+// Listings in these countries can only be fetched or filtered by country.
+func (c Country) Regions() []Region {
+	regions := make([]Region, 0)
+	for _, region := range Regions.Members() {
+		if region.Country() == c {
+			regions = append(regions, region)
+		}
+	}
+	return regions
+}
+
+// HasRegion returns whether a region belongs to the country.
+func (c Country) HasRegion(region Region) bool {
+	return Regions.Contains(region) && region.Country() == c
+}
+
 func (c *Country) UnmarshalJSON(data []byte) error {
 	var countryString string
 	err := json.Unmarshal(data, &countryString)
@@ -70,14 +133,14 @@ func (r *Region) UnmarshalJSON(data []byte) error {
 	return nil
 }
 
+// UnmarshalText unmarshals a region from its code, name or one of its aliases. See ParseRegion.
 func (r *Region) UnmarshalText(data []byte) error {
-	regionString := string(data)
-	region := Regions.Parse(regionString)
-	if region == nil {
-		return fmt.Errorf("region '%s' is not valid", regionString)
+	region, err := ParseRegion(string(data))
+	if err != nil {
+		return err
 	}
 
-	*r = *region
+	*r = region
 	return nil
 }
 
@@ -99,3 +162,101 @@ var (
 
 	Regions = region.Enum()
 )
+
+// regionMetadata is the metadata of a region.
+type regionMetadata struct {
+	name    string
+	country Country
+	aliases []string
+}
+
+// regionsMetadata is the metadata of each region.
+var regionsMetadata = map[Region]regionMetadata{
+	RegionEastAnglia:     {name: "East Anglia", country: CountryUnitedKingdom},
+	RegionLondon:         {name: "London", country: CountryUnitedKingdom, aliases: []string{"Greater London"}},
+	RegionMidlands:       {name: "Midlands", country: CountryUnitedKingdom},
+	RegionNorth:          {name: "North", country: CountryUnitedKingdom, aliases: []string{"North of England"}},
+	RegionNorthEast:      {name: "North East", country: CountryUnitedKingdom},
+	RegionNorthernIsland: {name: "Northern Ireland", country: CountryUnitedKingdom},
+	RegionNorthWest:      {name: "North West", country: CountryUnitedKingdom},
+	RegionScotland:       {name: "Scotland", country: CountryUnitedKingdom},
+	RegionSouth:          {name: "South", country: CountryUnitedKingdom, aliases: []string{"South of England"}},
+	RegionSouthEast:      {name: "South East", country: CountryUnitedKingdom},
+	RegionSouthWest:      {name: "South West", country: CountryUnitedKingdom},
+	RegionWales:          {name: "Wales", country: CountryUnitedKingdom, aliases: []string{"Cymru"}},
+}
+
+// regionsByName is the regions keyed by their normalised code, short code, name and aliases.
+// Names that are shared by multiple regions are mapped to nil, so they are not ambiguously parsed.
+var regionsByName = func() map[string]*Region {
+	regionsByName := make(map[string]*Region)
+	addName := func(name string, region Region) {
+		name = normaliseRegionName(name)
+		existingRegion, ok := regionsByName[name]
+		if ok && (existingRegion == nil || *existingRegion != region) {
+			regionsByName[name] = nil
+			return
+		}
+		regionsByName[name] = &region
+	}
+
+	for _, region := range Regions.Members() {
+		addName(region.Value, region)
+		addName(region.ShortCode(), region)
+
+		metadata := regionsMetadata[region]
+		addName(metadata.name, region)
+		for _, alias := range metadata.aliases {
+			addName(alias, region)
+		}
+	}
+
+	return regionsByName
+}()
+
+// ParseRegion parses a region from its code (e.g. GBNW), its code without the country prefix (e.g. NW),
+// its name (e.g. North West) or one of its aliases (e.g. Greater London for London).
+// Parsing is case insensitive, and hyphens and underscores are treated as spaces.
+func ParseRegion(s string) (Region, error) {
+	region, ok := regionsByName[normaliseRegionName(s)]
+	if !ok {
+		return Region{}, fmt.Errorf("region '%s' is not valid", s)
+	}
+	if region == nil {
+		return Region{}, fmt.Errorf("region '%s' is ambiguous", s)
+	}
+	return *region, nil
+}
+
+// normaliseRegionName normalises a region name (or code) for lookup.
+func normaliseRegionName(name string) string {
+	name = strings.ToLower(name)
+	name = strings.NewReplacer("-", " ", "_", " ").Replace(name)
+	return strings.Join(strings.Fields(name), " ")
+}
+
+// String is the name of the region e.g. North West.
+// If the region is not valid, its code is returned.
+func (r Region) String() string {
+	metadata, ok := regionsMetadata[r]
+	if !ok {
+		return r.Value
+	}
+	return metadata.name
+}
+
+// ShortCode is the code of the region without its country prefix e.g. NW for GBNW.
+func (r Region) ShortCode() string {
+	return strings.TrimPrefix(r.Value, r.Country().Value)
+}
+
+// Aliases are the alternative names of the region, excluding its name and codes e.g. Greater London for London.
+func (r Region) Aliases() []string {
+	return slices.Clone(regionsMetadata[r].aliases)
+}
+
+// Country gets the country the region belongs to.
+// If the region is not valid, an empty country is returned.
+func (r Region) Country() Country {
+	return regionsMetadata[r].country
+}
@@ -18,3 +18,132 @@ func TestRegionMarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `"GBLO"`, string(data))
 }
+
+func TestCountryRegions(t *testing.T) {
+	require.Len(t, CountryUnitedKingdom.Regions(), 12)
+	require.Contains(t, CountryUnitedKingdom.Regions(), RegionLondon)
+	require.Empty(t, CountryIreland.Regions())
+	require.Empty(t, CountryFrance.Regions())
+
+	require.True(t, CountryUnitedKingdom.HasRegion(RegionLondon))
+	require.False(t, CountryIreland.HasRegion(RegionLondon))
+
+	// Every region should belong to exactly one country
+	for _, region := range Regions.Members() {
+		country := region.Country()
+		require.True(t, Countries.Contains(country), region.Value)
+		require.True(t, country.HasRegion(region), region.Value)
+	}
+	require.Equal(t, Country{}, Region{"XXXX"}.Country())
+}
+
+func TestApiLocationQuery(t *testing.T) {
+	query, err := apiLocationQuery(CountryUnitedKingdom, RegionLondon, RegionSouth)
+	require.NoError(t, err)
+	require.Equal(t, "countryCode=GB,regionCode=GBLO,regionCode=GBSO", query)
+
+	query, err = apiLocationQuery(CountryIreland)
+	require.NoError(t, err)
+	require.Equal(t, "countryCode=IE", query)
+
+	_, err = apiLocationQuery(CountryIreland, RegionLondon)
+	require.EqualError(t, err, "region 'GBLO' is not in country 'IE'")
+
+	_, err = apiLocationQuery(CountryUnitedKingdom, Region{"XXXX"})
+	require.EqualError(t, err, "region 'XXXX' is not valid")
+
+	_, err = apiLocationQuery(Country{"XX"})
+	require.Error(t, err)
+}
+
+func TestLocationInputValidate(t *testing.T) {
+	feedInput := FeedUrlInput{APIKey: "test", Country: CountryIreland}
+	require.NoError(t, feedInput.Validate())
+
+	feedInput.Regions = []Region{RegionLondon}
+	require.Error(t, feedInput.Validate())
+
+	_, err := FeedUrl(feedInput)
+	require.Error(t, err)
+
+	fetchInput := FetchTicketListingsInput{Country: CountryUnitedKingdom, Regions: []Region{RegionLondon}}
+	require.NoError(t, fetchInput.Validate())
+
+	fetchInput.Country = CountryIreland
+	require.Error(t, fetchInput.Validate())
+}
+
+func TestParseRegion(t *testing.T) {
+	tests := map[string]Region{
+		"GBNW":           RegionNorthWest,
+		"gbnw":           RegionNorthWest,
+		"NW":             RegionNorthWest,
+		"North West":     RegionNorthWest,
+		"north-west":     RegionNorthWest,
+		" NORTH_WEST ":   RegionNorthWest,
+		"London":         RegionLondon,
+		"Greater London": RegionLondon,
+	}
+	for name, expected := range tests {
+		region, err := ParseRegion(name)
+		require.NoError(t, err, name)
+		require.Equal(t, expected, region, name)
+	}
+
+	_, err := ParseRegion("Narnia")
+	require.EqualError(t, err, "region 'Narnia' is not valid")
+}
+
+func TestRegionMetadata(t *testing.T) {
+	require.Equal(t, "North West", RegionNorthWest.String())
+	require.Equal(t, "XXXX", Region{"XXXX"}.String())
+	require.Equal(t, "NW", RegionNorthWest.ShortCode())
+	require.Equal(t, []string{"Greater London"}, RegionLondon.Aliases())
+	require.Equal(t, CountryUnitedKingdom, RegionNorthWest.Country())
+
+	// Every region should have a name, and be parseable from its name
+	for _, region := range Regions.Members() {
+		require.NotEqual(t, region.Value, region.String())
+
+		parsedRegion, err := ParseRegion(region.String())
+		require.NoError(t, err)
+		require.Equal(t, region, parsedRegion)
+	}
+}
+
+func TestRegionUnmarshal(t *testing.T) {
+	var region Region
+	err := json.Unmarshal([]byte(`"GBLO"`), &region)
+	require.NoError(t, err)
+	require.Equal(t, RegionLondon, region)
+
+	// JSON should only accept region codes
+	err = json.Unmarshal([]byte(`"London"`), &region)
+	require.EqualError(t, err, "region 'London' is not valid")
+
+	err = region.UnmarshalText([]byte("London"))
+	require.NoError(t, err)
+	require.Equal(t, RegionLondon, region)
+
+	err = region.UnmarshalText([]byte("south west"))
+	require.NoError(t, err)
+	require.Equal(t, RegionSouthWest, region)
+
+	err = region.UnmarshalText([]byte("nowhere"))
+	require.Error(t, err)
+}
+
+func TestCountryTimeZone(t *testing.T) {
+	for _, country := range Countries.Members() {
+		timeZone, err := country.TimeZone()
+		require.NoError(t, err, country.Value)
+		require.NotNil(t, timeZone)
+	}
+
+	timeZone, err := CountryUnitedKingdom.TimeZone()
+	require.NoError(t, err)
+	require.Equal(t, "Europe/London", timeZone.String())
+
+	_, err = Country{"XX"}.TimeZone()
+	require.Error(t, err)
+}
@@ -0,0 +1,184 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+)
+
+// EmailDigest batches messages for each recipient over a time window, and sends them as a single email
+// (a digest) when the window ends. Messages in a digest are grouped by the event of their listing.
+//
+// Messages are added to the digest of a recipient using Add, or a notifier returned by Recipient, so recipients
+// can be sent different messages (e.g. from dispatchers with different filters). Digests are sent by Flush, or
+// periodically by Run. A digest that fails to send is kept, and sent with the next digest of the recipient.
+//
+// An EmailDigest is safe for concurrent use.
+type EmailDigest struct {
+	sender *EmailNotifier
+	window time.Duration
+
+	mutex   sync.Mutex
+	batches map[string]*digestBatch
+}
+
+// digestBatch is the messages batched for a recipient.
+type digestBatch struct {
+	startedAt time.Time
+	messages  []Message
+}
+
+// NewEmailDigest creates a digest that sends emails using an email notifier, with the messages batched for each
+// recipient over a time window. The recipients of the email notifier are not used.
+func NewEmailDigest(sender *EmailNotifier, window time.Duration) *EmailDigest {
+	return &EmailDigest{
+		sender:  sender,
+		window:  window,
+		batches: make(map[string]*digestBatch),
+	}
+}
+
+// Add adds a message to the digest of a recipient.
+// If the digest already contains a message of the same listing, the message replaces it.
+func (d *EmailDigest) Add(recipient string, message Message) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	batch, ok := d.batches[recipient]
+	if !ok {
+		batch = &digestBatch{startedAt: timeNow()}
+		d.batches[recipient] = batch
+	}
+	batch.add(message)
+}
+
+// Recipient gets a notifier that adds messages to the digest of a recipient. The notifier never returns an error.
+func (d *EmailDigest) Recipient(recipient string) Notifier {
+	return NotifierFunc(func(_ context.Context, message Message) error {
+		d.Add(recipient, message)
+		return nil
+	})
+}
+
+// Pending gets the number of messages waiting to be sent to a recipient.
+func (d *EmailDigest) Pending(recipient string) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	batch, ok := d.batches[recipient]
+	if !ok {
+		return 0
+	}
+	return len(batch.messages)
+}
+
+// Flush sends the digests of recipients whose window has ended.
+// An error is returned if any digest fails to send. Digests that fail to send are kept to be sent again.
+func (d *EmailDigest) Flush(ctx context.Context) error {
+	return d.flush(ctx, false)
+}
+
+// FlushAll sends the digests of all recipients, even if their window has not ended e.g. before shutting down.
+// An error is returned if any digest fails to send. Digests that fail to send are kept to be sent again.
+func (d *EmailDigest) FlushAll(ctx context.Context) error {
+	return d.flush(ctx, true)
+}
+
+// Run flushes digests periodically until the context is done, then sends any remaining digests.
+// The interval digests are flushed at is the window, up to a minute.
+// Errors sending digests are passed to onError (if not nil), and do not stop the digest running.
+func (d *EmailDigest) Run(ctx context.Context, onError func(error)) {
+	ticker := time.NewTicker(min(d.window, time.Minute))
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			// Send remaining digests, even though the context is done
+			err := d.FlushAll(context.WithoutCancel(ctx))
+			if err != nil && onError != nil {
+				onError(err)
+			}
+			return
+		case <-ticker.C:
+			err := d.Flush(ctx)
+			if err != nil && onError != nil {
+				onError(err)
+			}
+		}
+	}
+}
+
+func (d *EmailDigest) flush(ctx context.Context, all bool) error {
+	// Take the batches to send, so messages can be added while they are sending
+	d.mutex.Lock()
+	now := timeNow()
+	batches := make(map[string]*digestBatch)
+	for recipient, batch := range d.batches {
+		if all || now.Sub(batch.startedAt) >= d.window {
+			batches[recipient] = batch
+			delete(d.batches, recipient)
+		}
+	}
+	d.mutex.Unlock()
+
+	// Send in recipient order, so errors are deterministic
+	recipients := make([]string, 0, len(batches))
+	for recipient := range batches {
+		recipients = append(recipients, recipient)
+	}
+	slices.Sort(recipients)
+
+	var errs []error
+	for _, recipient := range recipients {
+		batch := batches[recipient]
+		err := d.send(ctx, recipient, batch.messages)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to send digest to '%s': %w", recipient, err))
+			d.restore(recipient, batch)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// send sends a digest of messages to a recipient.
+func (d *EmailDigest) send(ctx context.Context, recipient string, messages []Message) error {
+	data := newEmailData("", messages)
+	if len(messages) == 1 {
+		data.Subject = messages[0].Title
+	} else {
+		data.Subject = fmt.Sprintf("%d new ticket listings for %d event(s)", len(messages), len(data.Events))
+	}
+	return d.sender.send(ctx, []string{recipient}, data)
+}
+
+// restore restores a batch that failed to send, merging it with any messages added since it was taken.
+func (d *EmailDigest) restore(recipient string, batch *digestBatch) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	newBatch, ok := d.batches[recipient]
+	if ok {
+		for _, message := range newBatch.messages {
+			batch.add(message)
+		}
+	}
+	d.batches[recipient] = batch
+}
+
+// add adds a message to the batch, replacing any message of the same listing.
+func (b *digestBatch) add(message Message) {
+	key := listingKey(message)
+	idx := slices.IndexFunc(b.messages, func(existingMessage Message) bool {
+		return key != "" && listingKey(existingMessage) == key
+	})
+	if idx >= 0 {
+		b.messages[idx] = message
+		return
+	}
+	b.messages = append(b.messages, message)
+}
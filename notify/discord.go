@@ -0,0 +1,68 @@
+package notify
+
+import (
+	"context"
+	"time"
+)
+
+// discordEmbedColour is the colour of the Discord embed sidebar (twickets green).
+const discordEmbedColour = 0x36b37e
+
+// Discord limits the length of embed fields.
+// See https://discord.com/developers/docs/resources/message#embed-object-embed-limits
+const (
+	discordMaxTitleLength       = 256
+	discordMaxDescriptionLength = 4096
+)
+
+// DiscordNotifier is a notifier that posts messages to a Discord channel webhook as embeds.
+//
+// Each message is posted as an embed, with the message title linking to the ticket listing,
+// and the message body as the embed description.
+type DiscordNotifier struct {
+	webhookURL string
+	options    httpOptions
+}
+
+// NewDiscordNotifier creates a notifier that posts messages to a Discord channel webhook url.
+// See https://support.discord.com/hc/en-us/articles/228383668-Intro-to-Webhooks
+func NewDiscordNotifier(webhookURL string, opts ...HTTPOpt) *DiscordNotifier {
+	return &DiscordNotifier{
+		webhookURL: webhookURL,
+		options:    newHTTPOptions("", opts),
+	}
+}
+
+type discordWebhookBody struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title       string              `json:"title"`
+	URL         string              `json:"url,omitempty"`
+	Description string              `json:"description"`
+	Colour      int                 `json:"color"`
+	Footer      *discordEmbedFooter `json:"footer,omitempty"`
+	Timestamp   string              `json:"timestamp,omitempty"`
+}
+
+type discordEmbedFooter struct {
+	Text string `json:"text"`
+}
+
+func (n *DiscordNotifier) Notify(ctx context.Context, message Message) error {
+	embed := discordEmbed{
+		Title:       truncate(message.Title, discordMaxTitleLength),
+		URL:         message.listingURL(),
+		Description: truncate(message.Body, discordMaxDescriptionLength),
+		Colour:      discordEmbedColour,
+	}
+	if message.ListingId != "" {
+		embed.Footer = &discordEmbedFooter{Text: "Listing " + message.ListingId}
+	}
+	if !message.Listing.CreatedAt.IsZero() {
+		embed.Timestamp = message.Listing.CreatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return n.options.postJSON(ctx, n.webhookURL, discordWebhookBody{Embeds: []discordEmbed{embed}})
+}
@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDiscordNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	listing := testListing("123", "Oasis", 160)
+	listing.CreatedAt = twigots.UnixTime{Time: time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)}
+	message, err := DefaultRenderer().Render(listing)
+	require.NoError(t, err)
+
+	notifier := NewDiscordNotifier(server.URL + "/api/webhooks/1/token")
+	err = notifier.Notify(context.Background(), message)
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/api/webhooks/1/token", request.Path)
+	require.Equal(t, map[string]any{
+		"embeds": []any{map[string]any{
+			"title":       "Oasis",
+			"url":         "https://www.twickets.live/app/block/123,2",
+			"description": message.Body,
+			"color":       float64(discordEmbedColour),
+			"footer":      map[string]any{"text": "Listing 123"},
+			"timestamp":   "2025-06-01T12:30:00Z",
+		}},
+	}, request.Body)
+}
+
+func TestDiscordNotifierTruncates(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewDiscordNotifier(server.URL)
+	err := notifier.Notify(context.Background(), Message{Title: strings.Repeat("a", 300)})
+	require.NoError(t, err)
+
+	embed := server.request(t).Body["embeds"].([]any)[0].(map[string]any)
+	require.Equal(t, strings.Repeat("a", 255)+"…", embed["title"])
+	require.NotContains(t, embed, "url")
+	require.NotContains(t, embed, "footer")
+	require.NotContains(t, embed, "timestamp")
+}
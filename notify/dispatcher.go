@@ -0,0 +1,344 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+const (
+	// DefaultAttempts is the default number of attempts made to send a message to a sink.
+	DefaultAttempts = 3
+	// DefaultBackoff is the default time waited before retrying a failed message.
+	// The time waited doubles after every failed attempt.
+	DefaultBackoff = time.Second
+)
+
+// timeNow is the current time. This is a variable so it can be changed in tests.
+var timeNow = time.Now
+
+// Dispatcher renders ticket listings into messages and sends them to sinks (notifiers).
+//
+// Each sink is sent messages concurrently, and failed messages are retried with exponential backoff.
+// Listings are deduplicated per sink, so a listing is only sent to a sink once, even if dispatched again.
+// If a listing fails to send to a sink, dispatching it again will only send it to the sinks that failed.
+//
+// A Dispatcher is safe for concurrent use.
+type Dispatcher struct {
+	renderer *Renderer
+	sinks    []sink
+	policy   *AlertPolicy
+
+	attempts            int
+	backoff             time.Duration
+	deduplicationWindow time.Duration
+
+	// policyMutex serialises dispatches with a policy, so listings are decided and committed atomically
+	policyMutex sync.Mutex
+
+	mutex sync.Mutex
+	// sent is the time listings were sent to each sink, keyed by sink name then listing key
+	sent map[string]map[string]time.Time
+	// purgedAt is the time listings sent before the deduplication window were last forgotten
+	purgedAt time.Time
+}
+
+// sink is a named notifier.
+type sink struct {
+	name     string
+	notifier Notifier
+}
+
+// DispatcherOpt is an option for a dispatcher.
+type DispatcherOpt func(*Dispatcher)
+
+// WithSink adds a sink to send messages to. The name identifies the sink in errors, and must be unique.
+func WithSink(name string, notifier Notifier) DispatcherOpt {
+	return func(d *Dispatcher) {
+		d.sinks = append(d.sinks, sink{name: name, notifier: notifier})
+	}
+}
+
+// WithRenderer sets the renderer used to render listings into messages. Defaults to DefaultRenderer.
+func WithRenderer(renderer *Renderer) DispatcherOpt {
+	return func(d *Dispatcher) {
+		d.renderer = renderer
+	}
+}
+
+// WithPolicy sets the alert policy applied to listings before they are sent, to prevent users being flooded.
+// Listings held by the policy during quiet hours are sent as a single summary message after quiet hours end.
+// Listings (and summaries) are only recorded as alerted by the policy once sent to all sinks,
+// so listings that fail to send are alerted again when dispatched again. See AlertPolicy.
+// Listings that are not sent to any sink, as they have already been sent, are not recorded as alerted.
+//
+// Dispatches are applied to the policy one at a time, so the policy limits are not exceeded by concurrent dispatches.
+func WithPolicy(policy *AlertPolicy) DispatcherOpt {
+	return func(d *Dispatcher) {
+		d.policy = policy
+	}
+}
+
+// WithRetry sets the number of attempts made to send a message to a sink, and the time waited before retrying.
+// The time waited doubles after every failed attempt.
+// Defaults to DefaultAttempts and DefaultBackoff. Set attempts to 1 to not retry.
+func WithRetry(attempts int, backoff time.Duration) DispatcherOpt {
+	return func(d *Dispatcher) {
+		d.attempts = max(attempts, 1)
+		d.backoff = max(backoff, 0)
+	}
+}
+
+// WithDeduplicationWindow sets how long a listing sent to a sink is remembered for, to prevent it being sent again.
+// Set to <=0 to remember listings forever. Defaults to forever.
+func WithDeduplicationWindow(window time.Duration) DispatcherOpt {
+	return func(d *Dispatcher) {
+		d.deduplicationWindow = window
+	}
+}
+
+// NewDispatcher creates a dispatcher with the options specified. Use WithSink to add sinks.
+func NewDispatcher(opts ...DispatcherOpt) (*Dispatcher, error) {
+	dispatcher := &Dispatcher{
+		attempts: DefaultAttempts,
+		backoff:  DefaultBackoff,
+		sent:     make(map[string]map[string]time.Time),
+	}
+	for _, opt := range opts {
+		opt(dispatcher)
+	}
+
+	if dispatcher.renderer == nil {
+		dispatcher.renderer = DefaultRenderer()
+	}
+
+	for _, sink := range dispatcher.sinks {
+		if sink.notifier == nil {
+			return nil, fmt.Errorf("sink '%s' has no notifier", sink.name)
+		}
+		if _, ok := dispatcher.sent[sink.name]; ok {
+			return nil, fmt.Errorf("sink '%s' is not unique", sink.name)
+		}
+		dispatcher.sent[sink.name] = make(map[string]time.Time)
+	}
+
+	return dispatcher, nil
+}
+
+// Dispatch renders ticket listings into messages and sends them to all sinks, skipping listings that have already
+// been sent to a sink. If the dispatcher has an alert policy, it is applied to the listings first.
+//
+// An error is returned if any listing fails to render or send to any sink, after retrying.
+// Listings that fail to send to a sink can be dispatched again later.
+func (d *Dispatcher) Dispatch(ctx context.Context, listings ...twigots.TicketListing) error {
+	var errs []error
+	if d.policy != nil {
+		// Listings must be committed before the next dispatch decides its listings
+		d.policyMutex.Lock()
+		defer d.policyMutex.Unlock()
+
+		result := d.policy.Decide(listings...)
+		listings = result.Alerts
+		d.policy.CommitHeld(result.Held...)
+
+		if len(result.Summary) > 0 {
+			err := d.sendSummary(ctx, result.Summary)
+			if err != nil {
+				errs = append(errs, err)
+			} else {
+				d.policy.CommitSummary(result.Summary...)
+			}
+		}
+	}
+
+	for _, listing := range listings {
+		message, err := d.renderer.Render(listing)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to render listing '%s': %w", listing.Id, err))
+			continue
+		}
+
+		sent, err := d.Send(ctx, message)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if sent && d.policy != nil {
+			d.policy.Commit(listing)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Send sends a message to all sinks the message's listing has not already been sent to.
+// Returns whether the message was sent to any sink.
+//
+// An error is returned if the message fails to send to any sink, after retrying.
+func (d *Dispatcher) Send(ctx context.Context, message Message) (bool, error) {
+	return d.send(ctx, message, listingKey(message))
+}
+
+// send sends a message to all sinks the key has not already been sent to, returning whether the message was sent
+// to any sink. If the key is empty, the message is sent to all sinks, and is not deduplicated.
+func (d *Dispatcher) send(ctx context.Context, message Message, key string) (bool, error) {
+	var waitGroup sync.WaitGroup
+	sinkSent := make([]bool, len(d.sinks))
+	sinkErrs := make([]error, len(d.sinks))
+	for idx, sink := range d.sinks {
+		if key != "" && !d.claim(sink.name, key) {
+			continue
+		}
+
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+
+			err := d.sendWithRetry(ctx, sink, message)
+			if err != nil {
+				if key != "" {
+					d.release(sink.name, key)
+				}
+				sinkErrs[idx] = fmt.Errorf("failed to notify sink '%s' of %s: %w", sink.name, messageDescription(message), err)
+				return
+			}
+			sinkSent[idx] = true
+		}()
+	}
+	waitGroup.Wait()
+
+	return slices.Contains(sinkSent, true), errors.Join(sinkErrs...)
+}
+
+// sendSummary sends listings held during quiet hours to all sinks as a single summary message.
+// Summaries are deduplicated per sink by their listings, so a summary that fails to send to a sink is only
+// sent again to that sink.
+func (d *Dispatcher) sendSummary(ctx context.Context, listings []twigots.TicketListing) error {
+	message, err := d.renderSummary(listings)
+	if err != nil {
+		return err
+	}
+	_, err = d.send(ctx, message, summaryKey(listings))
+	return err
+}
+
+// renderSummary renders listings held during quiet hours into a single summary message,
+// listing the title and url of each listing.
+func (d *Dispatcher) renderSummary(listings []twigots.TicketListing) (Message, error) {
+	lines := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		message, err := d.renderer.Render(listing)
+		if err != nil {
+			return Message{}, fmt.Errorf("failed to render listing '%s': %w", listing.Id, err)
+		}
+		lines = append(lines, fmt.Sprintf("- %s: %s", message.Title, message.URL))
+	}
+
+	return Message{
+		Title: fmt.Sprintf("%d ticket listing(s) during quiet hours", len(listings)),
+		Body:  strings.Join(lines, "\n"),
+	}, nil
+}
+
+// sendWithRetry sends a message to a sink, retrying with exponential backoff if it fails.
+func (d *Dispatcher) sendWithRetry(ctx context.Context, sink sink, message Message) error {
+	backoff := d.backoff
+	var err error
+	for attempt := 1; attempt <= d.attempts; attempt++ {
+		err = sink.notifier.Notify(ctx, message)
+		if err == nil {
+			return nil
+		}
+		if attempt == d.attempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(err, ctx.Err())
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+	return fmt.Errorf("failed after %d attempt(s): %w", d.attempts, err)
+}
+
+// claim marks a listing as sent to a sink, returning false if it has already been sent (or is being sent).
+func (d *Dispatcher) claim(sinkName, key string) bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	now := timeNow()
+	d.purge(now)
+
+	sent := d.sent[sinkName]
+	if sentAt, ok := sent[key]; ok && !d.expired(sentAt, now) {
+		return false
+	}
+	sent[key] = now
+	return true
+}
+
+// purge forgets listings sent before the deduplication window, so they do not use memory forever.
+// Listings are only purged once per window, as expired listings are ignored by claim anyway.
+// The mutex must be held by the caller.
+func (d *Dispatcher) purge(now time.Time) {
+	if d.deduplicationWindow <= 0 || !d.expired(d.purgedAt, now) {
+		return
+	}
+	d.purgedAt = now
+
+	for _, sent := range d.sent {
+		for sentKey, sentAt := range sent {
+			if d.expired(sentAt, now) {
+				delete(sent, sentKey)
+			}
+		}
+	}
+}
+
+// expired returns whether a listing sent at a time is before the deduplication window, so can be sent again.
+func (d *Dispatcher) expired(sentAt, now time.Time) bool {
+	return d.deduplicationWindow > 0 && now.Sub(sentAt) >= d.deduplicationWindow
+}
+
+// release unmarks a listing as sent to a sink, so it can be sent again.
+func (d *Dispatcher) release(sinkName, key string) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	delete(d.sent[sinkName], key)
+}
+
+// messageDescription describes a message in errors.
+func messageDescription(message Message) string {
+	key := listingKey(message)
+	if key == "" {
+		return fmt.Sprintf("message '%s'", message.Title)
+	}
+	return fmt.Sprintf("listing '%s'", key)
+}
+
+// summaryKey gets the key used to deduplicate a summary of listings.
+// This is the ids of the listings, so summaries of the same listings have the same key.
+func summaryKey(listings []twigots.TicketListing) string {
+	ids := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		ids = append(ids, listing.Id)
+	}
+	return "summary:" + strings.Join(ids, ",")
+}
+
+// listingKey gets the key used to deduplicate a message.
+// This is the listing id, or the url if the message has no listing id.
+func listingKey(message Message) string {
+	if message.ListingId != "" {
+		return message.ListingId
+	}
+	return message.URL
+}
@@ -0,0 +1,191 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier is a notifier that records messages, failing a number of times first.
+type recordingNotifier struct {
+	mutex    sync.Mutex
+	failures int
+	attempts int
+	messages []Message
+}
+
+func (n *recordingNotifier) Notify(_ context.Context, message Message) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	n.attempts++
+	if n.failures > 0 {
+		n.failures--
+		return errors.New("failed")
+	}
+	n.messages = append(n.messages, message)
+	return nil
+}
+
+func TestDispatcherDeduplicates(t *testing.T) {
+	first := &recordingNotifier{}
+	second := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("first", first), WithSink("second", second))
+	require.NoError(t, err)
+
+	err = dispatcher.Dispatch(
+		context.Background(),
+		testListing("1", "Oasis", 160),
+		testListing("2", "Oasis", 160),
+		testListing("1", "Oasis", 160),
+	)
+	require.NoError(t, err)
+	err = dispatcher.Dispatch(context.Background(), testListing("2", "Oasis", 160), testListing("3", "Oasis", 160))
+	require.NoError(t, err)
+
+	for _, notifier := range []*recordingNotifier{first, second} {
+		require.Len(t, notifier.messages, 3)
+		require.Equal(t, "1", notifier.messages[0].ListingId)
+		require.Equal(t, "2", notifier.messages[1].ListingId)
+		require.Equal(t, "3", notifier.messages[2].ListingId)
+	}
+}
+
+func TestDispatcherRetries(t *testing.T) {
+	flaky := &recordingNotifier{failures: 2}
+	broken := &recordingNotifier{failures: 100}
+	working := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(
+		WithSink("flaky", flaky),
+		WithSink("broken", broken),
+		WithSink("working", working),
+		WithRetry(3, time.Millisecond),
+	)
+	require.NoError(t, err)
+
+	err = dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160))
+	require.ErrorContains(t, err, "failed to notify sink 'broken' of listing '1': failed after 3 attempt(s)")
+	require.NotContains(t, err.Error(), "flaky")
+
+	require.Equal(t, 3, flaky.attempts)
+	require.Len(t, flaky.messages, 1)
+	require.Equal(t, 3, broken.attempts)
+	require.Empty(t, broken.messages)
+	require.Len(t, working.messages, 1)
+
+	// Failed sinks should be retried on the next dispatch, but not successful sinks
+	broken.failures = 0
+	err = dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160))
+	require.NoError(t, err)
+	require.Len(t, broken.messages, 1)
+	require.Len(t, flaky.messages, 1)
+	require.Len(t, working.messages, 1)
+}
+
+func TestDispatcherRetryCancelled(t *testing.T) {
+	broken := &recordingNotifier{failures: 100}
+	dispatcher, err := NewDispatcher(WithSink("broken", broken), WithRetry(5, time.Hour))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = dispatcher.Dispatch(ctx, testListing("1", "Oasis", 160))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 1, broken.attempts)
+}
+
+func TestDispatcherDeduplicationWindow(t *testing.T) {
+	currentTime := time.Now()
+	timeNow = func() time.Time { return currentTime }
+	defer func() { timeNow = time.Now }()
+
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithDeduplicationWindow(time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160)))
+	currentTime = currentTime.Add(30 * time.Minute)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160)))
+	require.Len(t, notifier.messages, 1)
+
+	currentTime = currentTime.Add(30 * time.Minute)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160)))
+	require.Len(t, notifier.messages, 2)
+}
+
+func TestDispatcherPurgesOncePerWindow(t *testing.T) {
+	currentTime := time.Now()
+	timeNow = func() time.Time { return currentTime }
+	defer func() { timeNow = time.Now }()
+
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithDeduplicationWindow(time.Hour))
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "Oasis", 160)))
+	currentTime = currentTime.Add(30 * time.Minute)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("2", "Oasis", 160)))
+	require.Len(t, dispatcher.sent["sink"], 2)
+
+	// Listings before the window should be forgotten once the window has passed since the last purge
+	currentTime = currentTime.Add(31 * time.Minute)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("3", "Oasis", 160)))
+	require.Equal(t, []string{"2", "3"}, slices.Sorted(maps.Keys(dispatcher.sent["sink"])))
+
+	// Listings before the window that have not been forgotten yet should still be sent again
+	currentTime = currentTime.Add(34 * time.Minute)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("2", "Oasis", 160)))
+	require.Len(t, notifier.messages, 4)
+	require.Equal(t, "2", notifier.messages[3].ListingId)
+}
+
+func TestDispatcherSendWithoutListing(t *testing.T) {
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier))
+	require.NoError(t, err)
+
+	// Messages without a listing id or url should not be deduplicated
+	for range 2 {
+		sent, err := dispatcher.Send(context.Background(), Message{Title: "Title"})
+		require.NoError(t, err)
+		require.True(t, sent)
+	}
+	require.Len(t, notifier.messages, 2)
+	require.Empty(t, dispatcher.sent["sink"])
+
+	// Messages that have already been sent to all sinks should not be reported as sent
+	sent, err := dispatcher.Send(context.Background(), Message{Title: "Title", ListingId: "1"})
+	require.NoError(t, err)
+	require.True(t, sent)
+	sent, err = dispatcher.Send(context.Background(), Message{Title: "Title", ListingId: "1"})
+	require.NoError(t, err)
+	require.False(t, sent)
+	require.Len(t, notifier.messages, 3)
+}
+
+func TestNewDispatcherInvalid(t *testing.T) {
+	_, err := NewDispatcher(WithSink("sink", &recordingNotifier{}), WithSink("sink", &recordingNotifier{}))
+	require.Error(t, err)
+
+	_, err = NewDispatcher(WithSink("sink", nil))
+	require.Error(t, err)
+}
+
+func TestWriterNotifier(t *testing.T) {
+	var buffer bytes.Buffer
+	notifier := NewWriterNotifier(&buffer)
+
+	err := notifier.Notify(context.Background(), Message{Title: "Title", Body: "Body"})
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), Message{Title: "Title 2", Body: "Body 2"})
+	require.NoError(t, err)
+	require.Equal(t, "Title\nBody\n\nTitle 2\nBody 2\n\n", buffer.String())
+}
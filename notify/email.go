@@ -0,0 +1,422 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	htmltemplate "html/template"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"slices"
+	"strconv"
+	"strings"
+	texttemplate "text/template"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/template"
+)
+
+const (
+	// DefaultEmailTextTemplate is the default template of the plain text part of emails.
+	// See EmailData for the template data.
+	DefaultEmailTextTemplate = `{{range .Events}}{{if .Event.Name}}{{.Event.Name}}
+{{.Event.Venue.Name}} - {{eventStart .Event}}
+{{end}}{{range .Messages}}{{if .Listing.Id}}
+- {{.Listing.NumTickets}} ticket(s) at {{price .Listing.TicketPriceInclFee}} each ({{discount .Listing.Discount}} off)
+  {{.URL}}
+{{else}}
+{{.Title}}
+{{.Body}}
+{{end}}{{end}}
+{{end}}`
+
+	// DefaultEmailHTMLTemplate is the default template of the HTML part of emails.
+	// See EmailData for the template data.
+	DefaultEmailHTMLTemplate = `<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Subject}}</title></head>
+<body style="font-family: sans-serif;">
+{{range .Events}}{{if .Event.Name}}<h2>{{.Event.Name}}</h2>
+<p>{{.Event.Venue.Name}} - {{eventStart .Event}}</p>
+{{end}}<ul>
+{{range .Messages}}<li>
+{{if .Listing.Id}}<a href="{{.URL}}">{{.Listing.NumTickets}} ticket(s)</a>
+at {{price .Listing.TicketPriceInclFee}} each ({{discount .Listing.Discount}} off)
+{{else}}<a href="{{.URL}}">{{.Title}}</a><br>{{.Body}}
+{{end}}</li>
+{{end}}</ul>
+{{end}}</body>
+</html>`
+)
+
+// SMTPSecurity is the security used to connect to an SMTP server.
+type SMTPSecurity int
+
+const (
+	// SMTPSecurityStartTLS connects without TLS, then upgrades the connection using STARTTLS.
+	// Sending fails if the server does not support STARTTLS. Default port is 587.
+	SMTPSecurityStartTLS SMTPSecurity = iota
+	// SMTPSecurityTLS connects using TLS (also known as implicit TLS or SMTPS). Default port is 465.
+	SMTPSecurityTLS
+	// SMTPSecurityNone connects without TLS. This should only be used for local servers. Default port is 25.
+	SMTPSecurityNone
+)
+
+// SMTPConfig is the config of an SMTP server.
+type SMTPConfig struct {
+	Host string
+	// Port of the server. If 0, the default port of the security is used.
+	Port int
+
+	// Username and password used to authenticate. If username is empty, no authentication is used.
+	Username string
+	Password string
+
+	// Security used to connect to the server. Defaults to SMTPSecurityStartTLS.
+	Security SMTPSecurity
+	// TLSConfig is the TLS config used to connect to the server.
+	// If nil, the default config (verifying the server certificate against the host) is used.
+	TLSConfig *tls.Config
+}
+
+func (c SMTPConfig) address() string {
+	port := c.Port
+	if port == 0 {
+		switch c.Security {
+		case SMTPSecurityTLS:
+			port = 465
+		case SMTPSecurityNone:
+			port = 25
+		default:
+			port = 587
+		}
+	}
+	return net.JoinHostPort(c.Host, strconv.Itoa(port))
+}
+
+func (c SMTPConfig) tlsConfig() *tls.Config {
+	if c.TLSConfig == nil {
+		return &tls.Config{ServerName: c.Host}
+	}
+
+	tlsConfig := c.TLSConfig.Clone()
+	if tlsConfig.ServerName == "" {
+		tlsConfig.ServerName = c.Host
+	}
+	return tlsConfig
+}
+
+// EmailData is the data email templates are executed with.
+//
+// Templates can use any of the fields of the data, its events, and their messages, and the template helper
+// functions e.g. {{.Subject}}, {{range .Events}}{{.Event.Name}}{{range .Messages}}{{price .Listing.TwicketsFee}}...
+// See template.Funcs.
+type EmailData struct {
+	Subject string
+	// Events are the events of the messages in the email, each with their messages.
+	// Events are in date order, with messages without a listing last.
+	Events []EmailEvent
+}
+
+// EmailEvent is an event, and the messages of its ticket listings.
+type EmailEvent struct {
+	// Event of the messages. This is empty for messages without a listing.
+	Event    twigots.Event
+	Messages []Message
+}
+
+// newEmailData creates email data from messages, grouping the messages by event.
+func newEmailData(subject string, messages []Message) EmailData {
+	var events []EmailEvent
+	eventIndexes := make(map[string]int)
+	for _, message := range messages {
+		key := eventKey(message.Listing)
+		idx, ok := eventIndexes[key]
+		if !ok {
+			idx = len(events)
+			eventIndexes[key] = idx
+			events = append(events, EmailEvent{Event: message.Listing.Event})
+		}
+		events[idx].Messages = append(events[idx].Messages, message)
+	}
+
+	slices.SortStableFunc(events, func(a, b EmailEvent) int {
+		aHasListing, bHasListing := a.Messages[0].Listing.Id != "", b.Messages[0].Listing.Id != ""
+		switch {
+		case aHasListing && !bHasListing:
+			return -1
+		case !aHasListing && bHasListing:
+			return 1
+		default:
+			return a.Event.Date.Compare(b.Event.Date.Time)
+		}
+	})
+
+	return EmailData{
+		Subject: subject,
+		Events:  events,
+	}
+}
+
+// eventKey gets the key used to group messages by the event of their listing.
+// This is the event id, or the event name and date if the event has no id.
+// Messages without a listing are grouped together.
+func eventKey(listing twigots.TicketListing) string {
+	switch {
+	case listing.Id == "":
+		return ""
+	case listing.Event.Id != "":
+		return listing.Event.Id
+	default:
+		return listing.Event.Name + "|" + listing.Event.Date.Format(time.DateOnly)
+	}
+}
+
+// EmailNotifier is a notifier that sends messages as emails using an SMTP server.
+//
+// Each message is sent as a multipart email with plain text and HTML parts, with the message title as the subject.
+// See NewEmailDigest to send digests of multiple messages instead.
+type EmailNotifier struct {
+	smtp SMTPConfig
+	from string
+	to   []string
+
+	textTemplate *texttemplate.Template
+	htmlTemplate *htmltemplate.Template
+}
+
+// EmailOpt is an option for an email notifier.
+type EmailOpt func(*emailOptions)
+
+type emailOptions struct {
+	textTemplate string
+	htmlTemplate string
+}
+
+// WithEmailTextTemplate sets the template of the plain text part of emails. See EmailData for the template data.
+// Defaults to DefaultEmailTextTemplate.
+func WithEmailTextTemplate(textTemplate string) EmailOpt {
+	return func(o *emailOptions) {
+		o.textTemplate = textTemplate
+	}
+}
+
+// WithEmailHTMLTemplate sets the template of the HTML part of emails. See EmailData for the template data.
+// Defaults to DefaultEmailHTMLTemplate.
+func WithEmailHTMLTemplate(htmlTemplate string) EmailOpt {
+	return func(o *emailOptions) {
+		o.htmlTemplate = htmlTemplate
+	}
+}
+
+// NewEmailNotifier creates a notifier that sends messages from an address to recipients using an SMTP server.
+func NewEmailNotifier(config SMTPConfig, from string, to []string, opts ...EmailOpt) (*EmailNotifier, error) {
+	if config.Host == "" {
+		return nil, errors.New("smtp host is not set")
+	}
+	if from == "" {
+		return nil, errors.New("from address is not set")
+	}
+
+	options := emailOptions{
+		textTemplate: DefaultEmailTextTemplate,
+		htmlTemplate: DefaultEmailHTMLTemplate,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	textTemplate, err := texttemplate.New("text").
+		Option("missingkey=error").
+		Funcs(template.Funcs()).
+		Parse(options.textTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse text template: %w", err)
+	}
+
+	htmlTemplate, err := htmltemplate.New("html").
+		Option("missingkey=error").
+		Funcs(template.Funcs()).
+		Parse(options.htmlTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse html template: %w", err)
+	}
+
+	return &EmailNotifier{
+		smtp:         config,
+		from:         from,
+		to:           to,
+		textTemplate: textTemplate,
+		htmlTemplate: htmlTemplate,
+	}, nil
+}
+
+func (n *EmailNotifier) Notify(ctx context.Context, message Message) error {
+	if len(n.to) == 0 {
+		return errors.New("no recipients are set")
+	}
+	return n.send(ctx, n.to, newEmailData(message.Title, []Message{message}))
+}
+
+// send renders an email and sends it to recipients.
+func (n *EmailNotifier) send(ctx context.Context, to []string, data EmailData) error {
+	email, err := n.render(to, data)
+	if err != nil {
+		return err
+	}
+
+	err = n.sendMail(ctx, to, email)
+	if err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// render renders a multipart email with plain text and HTML parts.
+func (n *EmailNotifier) render(to []string, data EmailData) ([]byte, error) {
+	var text bytes.Buffer
+	err := n.textTemplate.Execute(&text, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render text template: %w", err)
+	}
+
+	var html bytes.Buffer
+	err = n.htmlTemplate.Execute(&html, data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render html template: %w", err)
+	}
+
+	var email bytes.Buffer
+	body := multipart.NewWriter(&email)
+
+	headers := [][2]string{
+		{"From", n.from},
+		{"To", strings.Join(to, ", ")},
+		{"Subject", mime.QEncoding.Encode("utf-8", data.Subject)},
+		{"Date", timeNow().Format(time.RFC1123Z)},
+		{"Message-Id", messageId(n.smtp.Host)},
+		{"MIME-Version", "1.0"},
+		{"Content-Type", "multipart/alternative; boundary=" + body.Boundary()},
+	}
+	for _, header := range headers {
+		fmt.Fprintf(&email, "%s: %s\r\n", header[0], header[1])
+	}
+	email.WriteString("\r\n")
+
+	// Parts should be in order of preference, with the most preferred (HTML) last
+	for _, part := range []struct {
+		contentType string
+		content     []byte
+	}{
+		{contentType: "text/plain; charset=utf-8", content: bytes.TrimSpace(text.Bytes())},
+		{contentType: "text/html; charset=utf-8", content: html.Bytes()},
+	} {
+		partWriter, err := body.CreatePart(textproto.MIMEHeader{
+			"Content-Type":              {part.contentType},
+			"Content-Transfer-Encoding": {"quoted-printable"},
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create email part: %w", err)
+		}
+
+		encoder := quotedprintable.NewWriter(partWriter)
+		_, err = encoder.Write(part.content)
+		if err != nil {
+			return nil, fmt.Errorf("failed to write email part: %w", err)
+		}
+		err = encoder.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to write email part: %w", err)
+		}
+	}
+
+	err = body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed to write email: %w", err)
+	}
+
+	return email.Bytes(), nil
+}
+
+// sendMail sends an email to recipients using the SMTP server.
+func (n *EmailNotifier) sendMail(ctx context.Context, to []string, email []byte) error {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", n.smtp.address())
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", err)
+	}
+	defer conn.Close()
+
+	// Close the connection if the context is done, to interrupt any in progress command
+	stop := context.AfterFunc(ctx, func() { conn.Close() })
+	defer stop()
+
+	if n.smtp.Security == SMTPSecurityTLS {
+		conn = tls.Client(conn, n.smtp.tlsConfig())
+	}
+
+	client, err := smtp.NewClient(conn, n.smtp.Host)
+	if err != nil {
+		return fmt.Errorf("failed to connect to smtp server: %w", errors.Join(err, ctx.Err()))
+	}
+	defer client.Close()
+
+	if n.smtp.Security == SMTPSecurityStartTLS {
+		if ok, _ := client.Extension("STARTTLS"); !ok {
+			return errors.New("smtp server does not support STARTTLS")
+		}
+		err = client.StartTLS(n.smtp.tlsConfig())
+		if err != nil {
+			return fmt.Errorf("failed to start tls: %w", err)
+		}
+	}
+
+	if n.smtp.Username != "" {
+		err = client.Auth(smtp.PlainAuth("", n.smtp.Username, n.smtp.Password, n.smtp.Host))
+		if err != nil {
+			return fmt.Errorf("failed to authenticate: %w", err)
+		}
+	}
+
+	err = client.Mail(n.from)
+	if err != nil {
+		return fmt.Errorf("failed to set sender: %w", err)
+	}
+	for _, recipient := range to {
+		err = client.Rcpt(recipient)
+		if err != nil {
+			return fmt.Errorf("failed to add recipient '%s': %w", recipient, err)
+		}
+	}
+
+	writer, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("failed to start data: %w", err)
+	}
+	_, err = writer.Write(email)
+	if err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+	err = writer.Close()
+	if err != nil {
+		return fmt.Errorf("failed to write data: %w", err)
+	}
+
+	return client.Quit()
+}
+
+// messageId generates a unique email message id.
+func messageId(host string) string {
+	randomBytes := make([]byte, 8)
+	_, _ = rand.Read(randomBytes)
+	return fmt.Sprintf("<%d.%s@%s>", timeNow().UnixNano(), hex.EncodeToString(randomBytes), host)
+}
@@ -0,0 +1,400 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/http/httptest"
+	"net/mail"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+// testSMTPServer is a minimal in-process SMTP server that records the emails it receives.
+type testSMTPServer struct {
+	listener  net.Listener
+	tlsConfig *tls.Config
+
+	// implicitTLS is whether connections use TLS from the start.
+	implicitTLS bool
+	// startTLS is whether STARTTLS is supported.
+	startTLS bool
+	// rejectRecipients is whether all recipients are rejected.
+	rejectRecipients bool
+
+	mutex  sync.Mutex
+	emails []testEmail
+}
+
+// testEmail is an email received by a test SMTP server.
+type testEmail struct {
+	From string
+	To   []string
+	// Auth is the decoded AUTH PLAIN credentials, if any.
+	Auth string
+	// TLS is whether the email was received over TLS.
+	TLS     bool
+	Message *mail.Message
+}
+
+// newTestSMTPServer creates and starts a test SMTP server, returning it and the TLS config clients should use.
+func newTestSMTPServer(t *testing.T, configure func(*testSMTPServer)) (*testSMTPServer, *tls.Config) {
+	// Reuse the certificate of a httptest server, which is valid for 127.0.0.1
+	httpServer := httptest.NewTLSServer(nil)
+	certificate := httpServer.TLS.Certificates[0]
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(httpServer.Certificate())
+	httpServer.Close()
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	server := &testSMTPServer{
+		listener:  listener,
+		tlsConfig: &tls.Config{Certificates: []tls.Certificate{certificate}},
+	}
+	if configure != nil {
+		configure(server)
+	}
+	t.Cleanup(func() { listener.Close() })
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			go server.handle(conn)
+		}
+	}()
+
+	return server, &tls.Config{RootCAs: rootCAs}
+}
+
+// config gets the config of the server, with the security specified.
+func (s *testSMTPServer) config(security SMTPSecurity, tlsConfig *tls.Config) SMTPConfig {
+	address := s.listener.Addr().(*net.TCPAddr)
+	return SMTPConfig{
+		Host:      address.IP.String(),
+		Port:      address.Port,
+		Security:  security,
+		TLSConfig: tlsConfig,
+	}
+}
+
+func (s *testSMTPServer) received() []testEmail {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return append([]testEmail(nil), s.emails...)
+}
+
+func (s *testSMTPServer) handle(conn net.Conn) {
+	defer func() { conn.Close() }()
+
+	isTLS := s.implicitTLS
+	if isTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	reader := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var email testEmail
+	reply("220 localhost ESMTP")
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		command, argument, _ := strings.Cut(line, " ")
+
+		switch strings.ToUpper(command) {
+		case "EHLO", "HELO":
+			reply("250-localhost")
+			if s.startTLS && !isTLS {
+				reply("250-STARTTLS")
+			}
+			reply("250 AUTH PLAIN")
+		case "STARTTLS":
+			reply("220 ready to start tls")
+			tlsConn := tls.Server(conn, s.tlsConfig)
+			if tlsConn.Handshake() != nil {
+				return
+			}
+			conn, reader, isTLS = tlsConn, bufio.NewReader(tlsConn), true
+		case "AUTH":
+			_, credentials, _ := strings.Cut(argument, " ")
+			decoded, _ := base64.StdEncoding.DecodeString(credentials)
+			email.Auth = string(decoded)
+			reply("235 authenticated")
+		case "MAIL":
+			email.From = strings.Trim(strings.TrimPrefix(argument, "FROM:"), "<>")
+			reply("250 ok")
+		case "RCPT":
+			if s.rejectRecipients {
+				reply("550 no such user")
+				continue
+			}
+			email.To = append(email.To, strings.Trim(strings.TrimPrefix(argument, "TO:"), "<>"))
+			reply("250 ok")
+		case "DATA":
+			reply("354 send data")
+			var data strings.Builder
+			for {
+				dataLine, err := reader.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if dataLine == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(dataLine, "."))
+			}
+
+			message, err := mail.ReadMessage(strings.NewReader(data.String()))
+			if err != nil {
+				reply("554 invalid message")
+				continue
+			}
+			email.Message = message
+			email.TLS = isTLS
+
+			s.mutex.Lock()
+			s.emails = append(s.emails, email)
+			s.mutex.Unlock()
+			email = testEmail{Auth: email.Auth}
+			reply("250 ok")
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 ok")
+		}
+	}
+}
+
+// emailParts gets the decoded parts of a multipart email, keyed by content type.
+func emailParts(t *testing.T, message *mail.Message) map[string]string {
+	mediaType, params, err := mime.ParseMediaType(message.Header.Get("Content-Type"))
+	require.NoError(t, err)
+	require.Equal(t, "multipart/alternative", mediaType)
+
+	parts := make(map[string]string)
+	reader := multipart.NewReader(message.Body, params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+
+		content, err := io.ReadAll(part)
+		require.NoError(t, err)
+		// Lines of emails end in CRLF, so normalise them to make comparisons easier
+		parts[part.Header.Get("Content-Type")] = strings.ReplaceAll(string(content), "\r\n", "\n")
+	}
+	return parts
+}
+
+func TestEmailNotifierStartTLS(t *testing.T) {
+	server, tlsConfig := newTestSMTPServer(t, func(s *testSMTPServer) { s.startTLS = true })
+
+	config := server.config(SMTPSecurityStartTLS, tlsConfig)
+	config.Username = "user"
+	config.Password = "password"
+	notifier, err := NewEmailNotifier(config, "alerts@example.com", []string{"alice@example.com", "bob@example.com"})
+	require.NoError(t, err)
+
+	message, err := DefaultRenderer().Render(testListing("123", "Oasis", 160))
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), message)
+	require.NoError(t, err)
+
+	emails := server.received()
+	require.Len(t, emails, 1)
+	email := emails[0]
+	require.True(t, email.TLS)
+	require.Equal(t, "\x00user\x00password", email.Auth)
+	require.Equal(t, "alerts@example.com", email.From)
+	require.Equal(t, []string{"alice@example.com", "bob@example.com"}, email.To)
+	require.Equal(t, "Oasis", email.Message.Header.Get("Subject"))
+	require.Equal(t, "alice@example.com, bob@example.com", email.Message.Header.Get("To"))
+
+	parts := emailParts(t, email.Message)
+	require.Equal(t, `Oasis
+Wembley Stadium - Sat 12 Jul 2025
+
+- 2 ticket(s) at £90.00 each (10.00% off)
+  https://www.twickets.live/app/block/123,2`, parts["text/plain; charset=utf-8"])
+	require.Contains(t, parts["text/html; charset=utf-8"], "<h2>Oasis</h2>")
+	require.Contains(t, parts["text/html; charset=utf-8"],
+		`<a href="https://www.twickets.live/app/block/123,2">2 ticket(s)</a>`)
+}
+
+func TestEmailNotifierTLS(t *testing.T) {
+	server, tlsConfig := newTestSMTPServer(t, func(s *testSMTPServer) { s.implicitTLS = true })
+
+	notifier, err := NewEmailNotifier(server.config(SMTPSecurityTLS, tlsConfig), "alerts@example.com",
+		[]string{"alice@example.com"})
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), Message{Title: "Oasis <3", Body: "Tickets & more"})
+	require.NoError(t, err)
+
+	emails := server.received()
+	require.Len(t, emails, 1)
+	require.True(t, emails[0].TLS)
+	require.Empty(t, emails[0].Auth)
+
+	parts := emailParts(t, emails[0].Message)
+	require.Equal(t, "Oasis <3\nTickets & more", parts["text/plain; charset=utf-8"])
+	require.Contains(t, parts["text/html; charset=utf-8"], "Oasis &lt;3</a><br>Tickets &amp; more")
+}
+
+func TestEmailNotifierNoTLS(t *testing.T) {
+	server, _ := newTestSMTPServer(t, nil)
+
+	notifier, err := NewEmailNotifier(server.config(SMTPSecurityNone, nil), "alerts@example.com",
+		[]string{"alice@example.com"}, WithEmailTextTemplate("{{.Subject}}!"), WithEmailHTMLTemplate("<p>{{.Subject}}</p>"))
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), Message{Title: "Ticket alert £"})
+	require.NoError(t, err)
+
+	emails := server.received()
+	require.Len(t, emails, 1)
+	require.False(t, emails[0].TLS)
+
+	// Non-ASCII subjects should be encoded
+	subject, err := new(mime.WordDecoder).DecodeHeader(emails[0].Message.Header.Get("Subject"))
+	require.NoError(t, err)
+	require.Equal(t, "Ticket alert £", subject)
+
+	parts := emailParts(t, emails[0].Message)
+	require.Equal(t, "Ticket alert £!", parts["text/plain; charset=utf-8"])
+	require.Equal(t, "<p>Ticket alert £</p>", parts["text/html; charset=utf-8"])
+}
+
+func TestEmailNotifierErrors(t *testing.T) {
+	// STARTTLS is required, but not supported by the server
+	server, tlsConfig := newTestSMTPServer(t, nil)
+	notifier, err := NewEmailNotifier(server.config(SMTPSecurityStartTLS, tlsConfig), "alerts@example.com",
+		[]string{"alice@example.com"})
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), Message{Title: "Oasis"})
+	require.ErrorContains(t, err, "smtp server does not support STARTTLS")
+
+	// Recipient is rejected
+	server, _ = newTestSMTPServer(t, func(s *testSMTPServer) { s.rejectRecipients = true })
+	notifier, err = NewEmailNotifier(server.config(SMTPSecurityNone, nil), "alerts@example.com",
+		[]string{"alice@example.com"})
+	require.NoError(t, err)
+	err = notifier.Notify(context.Background(), Message{Title: "Oasis"})
+	require.ErrorContains(t, err, "failed to add recipient 'alice@example.com'")
+	require.Empty(t, server.received())
+
+	// Invalid templates
+	_, err = NewEmailNotifier(SMTPConfig{Host: "localhost"}, "alerts@example.com", nil,
+		WithEmailHTMLTemplate("{{.Subject"))
+	require.Error(t, err)
+	_, err = NewEmailNotifier(SMTPConfig{}, "alerts@example.com", nil)
+	require.Error(t, err)
+}
+
+func TestEmailDigest(t *testing.T) {
+	currentTime := time.Now()
+	timeNow = func() time.Time { return currentTime }
+	defer func() { timeNow = time.Now }()
+
+	server, _ := newTestSMTPServer(t, nil)
+	sender, err := NewEmailNotifier(server.config(SMTPSecurityNone, nil), "alerts@example.com", nil)
+	require.NoError(t, err)
+	digest := NewEmailDigest(sender, time.Hour)
+
+	renderer := DefaultRenderer()
+	render := func(id, eventId, eventName string, day int) Message {
+		listing := testListing(id, "Oasis", 160)
+		listing.Event.Id = eventId
+		listing.Event.Name = eventName
+		listing.Event.Date = twigots.Date{Time: time.Date(2025, 7, day, 0, 0, 0, 0, time.UTC)}
+		message, err := renderer.Render(listing)
+		require.NoError(t, err)
+		return message
+	}
+
+	alice := digest.Recipient("alice@example.com")
+	require.NoError(t, alice.Notify(context.Background(), render("1", "oasis-13", "Oasis", 13)))
+	require.NoError(t, alice.Notify(context.Background(), render("2", "oasis-12", "Oasis", 12)))
+	require.NoError(t, alice.Notify(context.Background(), render("3", "oasis-13", "Oasis", 13)))
+	require.NoError(t, alice.Notify(context.Background(), render("1", "oasis-13", "Oasis", 13)))
+
+	currentTime = currentTime.Add(30 * time.Minute)
+	digest.Add("bob@example.com", render("4", "blur", "Blur", 20))
+
+	// Window has not ended for either recipient
+	require.NoError(t, digest.Flush(context.Background()))
+	require.Empty(t, server.received())
+	require.Equal(t, 3, digest.Pending("alice@example.com"))
+
+	// Window has ended for alice only
+	currentTime = currentTime.Add(30 * time.Minute)
+	require.NoError(t, digest.Flush(context.Background()))
+	emails := server.received()
+	require.Len(t, emails, 1)
+	require.Zero(t, digest.Pending("alice@example.com"))
+	require.Equal(t, 1, digest.Pending("bob@example.com"))
+
+	email := emails[0]
+	require.Equal(t, []string{"alice@example.com"}, email.To)
+	require.Equal(t, "3 new ticket listings for 2 event(s)", email.Message.Header.Get("Subject"))
+
+	// Listings should be grouped by event, in date order
+	text := emailParts(t, email.Message)["text/plain; charset=utf-8"]
+	require.Equal(t, `Oasis
+Wembley Stadium - Sat 12 Jul 2025
+
+- 2 ticket(s) at £90.00 each (10.00% off)
+  https://www.twickets.live/app/block/2,2
+
+Oasis
+Wembley Stadium - Sun 13 Jul 2025
+
+- 2 ticket(s) at £90.00 each (10.00% off)
+  https://www.twickets.live/app/block/1,2
+
+- 2 ticket(s) at £90.00 each (10.00% off)
+  https://www.twickets.live/app/block/3,2`, text)
+
+	// Remaining digests should be sent when flushing all
+	require.NoError(t, digest.FlushAll(context.Background()))
+	emails = server.received()
+	require.Len(t, emails, 2)
+	require.Equal(t, []string{"bob@example.com"}, emails[1].To)
+	require.Equal(t, "Blur", emails[1].Message.Header.Get("Subject"))
+}
+
+func TestEmailDigestSendFailure(t *testing.T) {
+	server, _ := newTestSMTPServer(t, func(s *testSMTPServer) { s.rejectRecipients = true })
+	sender, err := NewEmailNotifier(server.config(SMTPSecurityNone, nil), "alerts@example.com", nil)
+	require.NoError(t, err)
+	digest := NewEmailDigest(sender, time.Hour)
+
+	digest.Add("alice@example.com", Message{Title: "Oasis", ListingId: "1"})
+	err = digest.FlushAll(context.Background())
+	require.ErrorContains(t, err, "failed to send digest to 'alice@example.com'")
+
+	// Failed digests should be kept, and merged with new messages
+	digest.Add("alice@example.com", Message{Title: "Blur", ListingId: "2"})
+	require.Equal(t, 2, digest.Pending("alice@example.com"))
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"strings"
+)
+
+// gotifyPriorities are the Gotify priorities (0 to 10) of each Priority.
+var gotifyPriorities = map[Priority]int{
+	PriorityMin:     0,
+	PriorityLow:     2,
+	PriorityDefault: 5,
+	PriorityHigh:    8,
+	PriorityMax:     10,
+}
+
+// GotifyNotifier is a notifier that sends messages to a Gotify server.
+//
+// Each message is sent with the message title and body, the priority set using WithPriority,
+// and a click url linking to the ticket listing.
+type GotifyNotifier struct {
+	serverURL string
+	options   httpOptions
+}
+
+// NewGotifyNotifier creates a notifier that sends messages to a Gotify server
+// using the token of a Gotify application.
+// See https://gotify.net/docs/pushmsg
+func NewGotifyNotifier(serverURL, appToken string, opts ...HTTPOpt) *GotifyNotifier {
+	options := newHTTPOptions("", opts)
+	// The token is sent as a header, rather than a query parameter, so it is not logged
+	options.header.Set("X-Gotify-Key", appToken)
+
+	return &GotifyNotifier{
+		serverURL: strings.TrimSuffix(serverURL, "/"),
+		options:   options,
+	}
+}
+
+type gotifyMessageBody struct {
+	Title    string         `json:"title"`
+	Message  string         `json:"message"`
+	Priority int            `json:"priority"`
+	Extras   map[string]any `json:"extras,omitempty"`
+}
+
+func (n *GotifyNotifier) Notify(ctx context.Context, message Message) error {
+	body := gotifyMessageBody{
+		Title:    message.Title,
+		Message:  message.Body,
+		Priority: gotifyPriorities[n.options.priority],
+	}
+
+	url := message.listingURL()
+	if url != "" {
+		// See https://gotify.net/docs/msgextras#clientnotification
+		body.Extras = map[string]any{
+			"client::notification": map[string]any{
+				"click": map[string]string{"url": url},
+			},
+		}
+	}
+
+	return n.options.postJSON(ctx, n.serverURL+"/message", body)
+}
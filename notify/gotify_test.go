@@ -0,0 +1,46 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGotifyNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewGotifyNotifier(server.URL+"/", "app-token", WithPriority(PriorityHigh))
+	err := notifier.Notify(context.Background(), Message{
+		Title: "Oasis",
+		Body:  "2 ticket(s)",
+		URL:   "https://www.twickets.live/app/block/123,2",
+	})
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/message", request.Path)
+	require.Equal(t, "app-token", request.Header.Get("X-Gotify-Key"))
+	require.Equal(t, map[string]any{
+		"title":    "Oasis",
+		"message":  "2 ticket(s)",
+		"priority": float64(8),
+		"extras": map[string]any{
+			"client::notification": map[string]any{
+				"click": map[string]any{"url": "https://www.twickets.live/app/block/123,2"},
+			},
+		},
+	}, request.Body)
+}
+
+func TestGotifyNotifierDefaultPriority(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewGotifyNotifier(server.URL, "app-token")
+	err := notifier.Notify(context.Background(), Message{Title: "Oasis"})
+	require.NoError(t, err)
+
+	body := server.request(t).Body
+	require.Equal(t, float64(5), body["priority"])
+	require.NotContains(t, body, "extras")
+}
@@ -0,0 +1,129 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodyLength is the maximum length of a response body included in an error.
+const maxErrorBodyLength = 512
+
+// Priority is the priority of a notification, for services that support priorities (ntfy and Gotify).
+// Priorities are mapped to the priority levels of each service.
+type Priority int
+
+const (
+	PriorityMin Priority = iota + 1
+	PriorityLow
+	PriorityDefault
+	PriorityHigh
+	PriorityMax
+)
+
+// HTTPOpt is an option for a notifier that sends messages over http.
+type HTTPOpt func(*httpOptions)
+
+type httpOptions struct {
+	client   *http.Client
+	header   http.Header
+	baseURL  string
+	priority Priority
+	tags     []string
+}
+
+// WithHTTPClient sets the http client used to send requests. Defaults to http.DefaultClient.
+func WithHTTPClient(client *http.Client) HTTPOpt {
+	return func(o *httpOptions) {
+		o.client = client
+	}
+}
+
+// WithHeader sets a header sent with every request e.g. an authorization header.
+func WithHeader(key, value string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.header.Set(key, value)
+	}
+}
+
+// WithBaseURL sets the base url of the service messages are sent to e.g. a self-hosted ntfy server.
+// This only applies to services that have a default base url (Telegram and ntfy).
+// Notifiers created with a full url (webhook, Discord, Slack and Gotify) send messages to that url.
+func WithBaseURL(baseURL string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.baseURL = strings.TrimSuffix(baseURL, "/")
+	}
+}
+
+// WithPriority sets the priority of messages. This only applies to ntfy and Gotify. Defaults to PriorityDefault.
+func WithPriority(priority Priority) HTTPOpt {
+	return func(o *httpOptions) {
+		o.priority = min(max(priority, PriorityMin), PriorityMax)
+	}
+}
+
+// WithTags sets the tags of messages e.g. "tickets" or "tada".
+// Tags matching emoji short codes are shown as emojis. This only applies to ntfy.
+func WithTags(tags ...string) HTTPOpt {
+	return func(o *httpOptions) {
+		o.tags = tags
+	}
+}
+
+func newHTTPOptions(defaultBaseURL string, opts []HTTPOpt) httpOptions {
+	options := httpOptions{
+		client:   http.DefaultClient,
+		header:   make(http.Header),
+		baseURL:  defaultBaseURL,
+		priority: PriorityDefault,
+	}
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// postJSON posts a JSON body to a url, returning an error if the response is not successful.
+func (o httpOptions) postJSON(ctx context.Context, url string, body any) error {
+	bodyBytes, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to encode request body: %w", err)
+	}
+
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(bodyBytes))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range o.header {
+		request.Header[key] = values
+	}
+	request.Header.Set("Content-Type", "application/json")
+
+	response, err := o.client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to send request: %w", err)
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode > 299 {
+		errorBody, _ := io.ReadAll(io.LimitReader(response.Body, maxErrorBodyLength))
+		return fmt.Errorf("request failed: %s: %s", response.Status, strings.TrimSpace(string(errorBody)))
+	}
+
+	// Read the body so the connection can be reused
+	_, _ = io.Copy(io.Discard, response.Body)
+	return nil
+}
+
+// truncate truncates a string to a maximum number of characters, ending it with an ellipsis if truncated.
+func truncate(s string, maxLength int) string {
+	runes := []rune(s)
+	if len(runes) <= maxLength {
+		return s
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
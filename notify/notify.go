@@ -0,0 +1,138 @@
+// Package notify sends notifications of ticket listings, such as to a webhook or a chat service.
+//
+// Listings are rendered into messages using a Renderer, and sent to one or more notifiers (sinks)
+// using a Dispatcher, which retries failed notifications and prevents duplicate notifications.
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/template"
+)
+
+const (
+	// DefaultTitleTemplate is the default template of message titles.
+	DefaultTitleTemplate = `{{.Event.Name}}`
+
+	// DefaultBodyTemplate is the default template of message bodies.
+	DefaultBodyTemplate = `{{.NumTickets}} ticket(s) at {{.Event.Venue.Name}} on {{eventStart .Event}}
+Price: {{price .TicketPrice}} per ticket ({{price .TotalPrice}} total incl fee)
+Original price: {{price .OriginalTicketPrice}} per ticket
+Discount: {{discount .Discount}}
+{{listingURL .}}`
+)
+
+// Message is a notification message of a ticket listing.
+type Message struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+	// URL is the url of the ticket listing.
+	URL string `json:"url"`
+	// ListingId is the id of the ticket listing.
+	ListingId string `json:"listingId"`
+
+	// Listing is the ticket listing the message is about.
+	// This can be used by notifiers to add extra details to the message.
+	Listing twigots.TicketListing `json:"-"`
+}
+
+// listingURL gets the url of the message's ticket listing.
+// If the message has no url, the url is built from the message's listing, if set.
+func (m Message) listingURL() string {
+	if m.URL != "" || m.Listing.Id == "" {
+		return m.URL
+	}
+	return twigots.ListingURL(m.Listing.Id, m.Listing.NumTickets)
+}
+
+// Notifier sends notification messages e.g. to a webhook or chat service.
+type Notifier interface {
+	Notify(ctx context.Context, message Message) error
+}
+
+// NotifierFunc is a function that can be used as a Notifier.
+type NotifierFunc func(ctx context.Context, message Message) error
+
+// Notify calls f(ctx, message).
+func (f NotifierFunc) Notify(ctx context.Context, message Message) error {
+	return f(ctx, message)
+}
+
+// Renderer renders ticket listings into messages using templates.
+//
+// Templates are executed with the view of the ticket listing, so can use any of its fields and the template
+// helper functions e.g. {{.Event.Name}}, {{.Event.Venue.Name}}, {{price .TicketPrice}} or {{discount .Discount}}.
+// See template.Listing and template.Funcs.
+type Renderer struct {
+	title *template.Template
+	body  *template.Template
+}
+
+// NewRenderer creates a renderer from title and body text templates. See Renderer for the template data.
+// If a template is empty, the default template is used.
+// Templates are validated against sample listings. See template.Parse.
+func NewRenderer(titleTemplate, bodyTemplate string) (*Renderer, error) {
+	if titleTemplate == "" {
+		titleTemplate = DefaultTitleTemplate
+	}
+	if bodyTemplate == "" {
+		bodyTemplate = DefaultBodyTemplate
+	}
+
+	title, err := template.Parse("title", titleTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse title template: %w", err)
+	}
+
+	body, err := template.Parse("body", bodyTemplate)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse body template: %w", err)
+	}
+
+	return NewRendererFromTemplates(title, body), nil
+}
+
+// NewRendererFromTemplates creates a renderer from title and body templates e.g. loaded using template.Load.
+func NewRendererFromTemplates(title, body *template.Template) *Renderer {
+	return &Renderer{
+		title: title,
+		body:  body,
+	}
+}
+
+// DefaultRenderer creates a renderer using the default templates.
+func DefaultRenderer() *Renderer {
+	renderer, err := NewRenderer("", "")
+	if err != nil {
+		// An error will never occur if the default templates are valid.
+		// If an error does occur (due to an error in the templates), panic so we catch it.
+		panic(err)
+	}
+	return renderer
+}
+
+// Render renders a ticket listing into a message.
+func (r *Renderer) Render(listing twigots.TicketListing) (Message, error) {
+	view := template.NewListing(listing)
+
+	title, err := r.title.ExecuteView(view)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to render title: %w", err)
+	}
+
+	body, err := r.body.ExecuteView(view)
+	if err != nil {
+		return Message{}, fmt.Errorf("failed to render body: %w", err)
+	}
+
+	return Message{
+		Title:     strings.TrimSpace(title),
+		Body:      strings.TrimSpace(body),
+		URL:       view.URL,
+		ListingId: listing.Id,
+		Listing:   listing,
+	}, nil
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRenderer(t *testing.T) {
+	message, err := DefaultRenderer().Render(testListing("123", "Oasis", 160))
+	require.NoError(t, err)
+
+	require.Equal(t, "Oasis", message.Title)
+	require.Equal(t, `2 ticket(s) at Wembley Stadium on Sat 12 Jul 2025
+Price: £90.00 per ticket (£180.00 total incl fee)
+Original price: £100.00 per ticket
+Discount: 10.00%
+https://www.twickets.live/app/block/123,2`, message.Body)
+	require.Equal(t, "https://www.twickets.live/app/block/123,2", message.URL)
+	require.Equal(t, "123", message.ListingId)
+}
+
+func TestNewRenderer(t *testing.T) {
+	renderer, err := NewRenderer("{{.Event.Name}} - {{discount .Discount}} off", "{{.Event.Venue.Name}}")
+	require.NoError(t, err)
+
+	message, err := renderer.Render(testListing("123", "Oasis", 160))
+	require.NoError(t, err)
+	require.Equal(t, "Oasis - 10.00% off", message.Title)
+	require.Equal(t, "Wembley Stadium", message.Body)
+
+	_, err = NewRenderer("{{.Event.Name", "")
+	require.Error(t, err)
+
+	// Templates should be validated when they are created
+	_, err = NewRenderer("{{.NotAField}}", "")
+	require.Error(t, err)
+}
+
+// testListing creates a ticket listing of 2 tickets for an event, with a total price (excl fee) in pounds.
+func testListing(id, eventName string, totalPrice int) twigots.TicketListing {
+	return twigots.TicketListing{
+		Id:                 id,
+		NumTickets:         2,
+		TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: totalPrice * 100},
+		TwicketsFee:        twigots.Price{Currency: twigots.CurrencyGBP, Amount: 20 * 100},
+		OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+		Event: twigots.Event{
+			Name:  eventName,
+			Date:  twigots.Date{Time: time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)},
+			Venue: twigots.Venue{Name: "Wembley Stadium"},
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"context"
+)
+
+// DefaultNtfyBaseURL is the default base url of the ntfy server.
+const DefaultNtfyBaseURL = "https://ntfy.sh"
+
+// DefaultNtfyTags are the default tags of ntfy messages.
+var DefaultNtfyTags = []string{"tickets"}
+
+// NtfyNotifier is a notifier that publishes messages to a ntfy topic.
+//
+// Each message is published with the message title and body, the priority and tags set using
+// WithPriority and WithTags, and a "Buy" action linking to the ticket listing.
+type NtfyNotifier struct {
+	topic   string
+	options httpOptions
+}
+
+// NewNtfyNotifier creates a notifier that publishes messages to a ntfy topic.
+// See https://docs.ntfy.sh/publish/#publish-as-json
+//
+// Messages are published to DefaultNtfyBaseURL, unless set using WithBaseURL.
+// Use WithHeader to set an authorization header for protected topics.
+func NewNtfyNotifier(topic string, opts ...HTTPOpt) *NtfyNotifier {
+	opts = append([]HTTPOpt{WithTags(DefaultNtfyTags...)}, opts...)
+	return &NtfyNotifier{
+		topic:   topic,
+		options: newHTTPOptions(DefaultNtfyBaseURL, opts),
+	}
+}
+
+type ntfyPublishBody struct {
+	Topic    string       `json:"topic"`
+	Title    string       `json:"title"`
+	Message  string       `json:"message"`
+	Priority int          `json:"priority"`
+	Tags     []string     `json:"tags,omitempty"`
+	Click    string       `json:"click,omitempty"`
+	Actions  []ntfyAction `json:"actions,omitempty"`
+}
+
+type ntfyAction struct {
+	Action string `json:"action"`
+	Label  string `json:"label"`
+	URL    string `json:"url"`
+}
+
+func (n *NtfyNotifier) Notify(ctx context.Context, message Message) error {
+	body := ntfyPublishBody{
+		Topic:   n.topic,
+		Title:   message.Title,
+		Message: message.Body,
+		// ntfy priorities are 1 (min) to 5 (max), matching Priority
+		Priority: int(n.options.priority),
+		Tags:     n.options.tags,
+	}
+
+	url := message.listingURL()
+	if url != "" {
+		body.Click = url
+		body.Actions = []ntfyAction{{Action: "view", Label: "Buy", URL: url}}
+	}
+
+	// JSON messages are published to the root url, with the topic in the body
+	return n.options.postJSON(ctx, n.options.baseURL+"/", body)
+}
@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNtfyNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewNtfyNotifier("tickets", WithBaseURL(server.URL), WithHeader("Authorization", "Bearer token"))
+	err := notifier.Notify(context.Background(), Message{
+		Title: "Oasis",
+		Body:  "2 ticket(s)",
+		URL:   "https://www.twickets.live/app/block/123,2",
+	})
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/", request.Path)
+	require.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	require.Equal(t, map[string]any{
+		"topic":    "tickets",
+		"title":    "Oasis",
+		"message":  "2 ticket(s)",
+		"priority": float64(3),
+		"tags":     []any{"tickets"},
+		"click":    "https://www.twickets.live/app/block/123,2",
+		"actions": []any{map[string]any{
+			"action": "view",
+			"label":  "Buy",
+			"url":    "https://www.twickets.live/app/block/123,2",
+		}},
+	}, request.Body)
+}
+
+func TestNtfyNotifierPriorityAndTags(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewNtfyNotifier(
+		"tickets",
+		WithBaseURL(server.URL),
+		WithPriority(PriorityMax),
+		WithTags("tada", "oasis"),
+	)
+	err := notifier.Notify(context.Background(), Message{Title: "Oasis"})
+	require.NoError(t, err)
+
+	body := server.request(t).Body
+	require.Equal(t, float64(5), body["priority"])
+	require.Equal(t, []any{"tada", "oasis"}, body["tags"])
+	require.NotContains(t, body, "click")
+	require.NotContains(t, body, "actions")
+}
@@ -0,0 +1,478 @@
+package notify
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// CooldownScope is what a cooldown applies to.
+type CooldownScope int
+
+const (
+	// CooldownPerEvent applies a cooldown to each event, so an alert for one event does not prevent alerts for
+	// other events. Events are identified by their id.
+	CooldownPerEvent CooldownScope = iota
+	// CooldownPerWatch applies a cooldown to all alerts of the policy (the watch), regardless of event.
+	CooldownPerWatch
+)
+
+// AlertPolicy decides which matched ticket listings should be alerted, to prevent users being flooded with alerts.
+//
+// A policy should be created for each watch (e.g. each set of filters of a user), and applied to the listings
+// matched by the watch before they are sent. See Apply, Decide and WithPolicy.
+// Policies can:
+//   - Apply a cooldown after each alert, per event or per watch. See WithCooldown.
+//   - Limit the number of alerts in a window of time. See WithMaxAlerts.
+//   - Hold alerts during quiet hours, and release them as a summary after. See WithQuietHours.
+//   - Only alert listings cheaper than the last listing alerted for the same event. See WithOnlyCheaper.
+//
+// Events are identified by their id, falling back to their name and date if they have no id.
+// Listings are compared using their ticket price, including fee.
+//
+// An AlertPolicy is safe for concurrent use.
+type AlertPolicy struct {
+	cooldown      time.Duration
+	cooldownScope CooldownScope
+
+	maxAlerts       int
+	maxAlertsWindow time.Duration
+
+	quietHoursStart    string
+	quietHoursEnd      string
+	quietHoursLocation *time.Location
+	quietHours         *quietHours
+
+	onlyCheaper bool
+
+	mutex sync.Mutex
+	// state is the record of listings alerted and held
+	state *alertState
+}
+
+// alertState is the record of listings alerted and held by a policy.
+type alertState struct {
+	// alerts are the last alert of each event, keyed by event key
+	alerts map[string]eventAlert
+	// alertedListings are all listings alerted, so a listing is never alerted twice
+	alertedListings map[alertedListing]struct{}
+	// lastAlertedAt is the time of the last alert of any event
+	lastAlertedAt time.Time
+	// alertTimes are the times of alerts within the max alerts window
+	alertTimes []time.Time
+	// held are the listings held during quiet hours
+	held []twigots.TicketListing
+}
+
+func newAlertState() *alertState {
+	return &alertState{
+		alerts:          make(map[string]eventAlert),
+		alertedListings: make(map[alertedListing]struct{}),
+	}
+}
+
+func (s *alertState) clone() *alertState {
+	return &alertState{
+		alerts:          maps.Clone(s.alerts),
+		alertedListings: maps.Clone(s.alertedListings),
+		lastAlertedAt:   s.lastAlertedAt,
+		alertTimes:      slices.Clone(s.alertTimes),
+		held:            slices.Clone(s.held),
+	}
+}
+
+// eventAlert is the last alert of an event.
+type eventAlert struct {
+	at    time.Time
+	price twigots.Price
+}
+
+// alertedListing identifies a listing alerted for an event.
+type alertedListing struct {
+	eventKey  string
+	listingId string
+}
+
+// AlertPolicyOpt is an option for an alert policy.
+type AlertPolicyOpt func(*AlertPolicy)
+
+// WithCooldown sets the time after an alert during which no more alerts are sent, per event or per watch.
+func WithCooldown(cooldown time.Duration, scope CooldownScope) AlertPolicyOpt {
+	return func(p *AlertPolicy) {
+		p.cooldown = cooldown
+		p.cooldownScope = scope
+	}
+}
+
+// WithMaxAlerts sets the maximum number of alerts sent in a (sliding) window of time e.g. 10 alerts per hour.
+func WithMaxAlerts(maxAlerts int, window time.Duration) AlertPolicyOpt {
+	return func(p *AlertPolicy) {
+		p.maxAlerts = maxAlerts
+		p.maxAlertsWindow = window
+	}
+}
+
+// WithQuietHours sets the hours during which alerts are held, in the time zone of the user.
+// Start and end are times of day in the format 15:04 e.g. 23:00 and 07:00. Quiet hours can span midnight.
+//
+// Listings matched during quiet hours are held, and released as a summary after quiet hours end.
+// Held listings are still subject to the only cheaper rule, but not cooldowns or max alerts.
+func WithQuietHours(start, end string, location *time.Location) AlertPolicyOpt {
+	return func(p *AlertPolicy) {
+		p.quietHoursStart = start
+		p.quietHoursEnd = end
+		p.quietHoursLocation = location
+	}
+}
+
+// WithOnlyCheaper sets that listings are only alerted if they are cheaper than the last listing alerted
+// for the same event.
+func WithOnlyCheaper() AlertPolicyOpt {
+	return func(p *AlertPolicy) {
+		p.onlyCheaper = true
+	}
+}
+
+// NewAlertPolicy creates an alert policy with the options specified.
+// With no options, all listings are alerted.
+func NewAlertPolicy(opts ...AlertPolicyOpt) (*AlertPolicy, error) {
+	policy := &AlertPolicy{
+		state: newAlertState(),
+	}
+	for _, opt := range opts {
+		opt(policy)
+	}
+
+	if policy.cooldown < 0 {
+		return nil, errors.New("cooldown must be >= 0")
+	}
+	if policy.maxAlerts < 0 || (policy.maxAlerts > 0 && policy.maxAlertsWindow <= 0) {
+		return nil, errors.New("max alerts must be >= 0, with a window > 0")
+	}
+
+	if policy.quietHoursStart != "" || policy.quietHoursEnd != "" {
+		quietHours, err := newQuietHours(policy.quietHoursStart, policy.quietHoursEnd, policy.quietHoursLocation)
+		if err != nil {
+			return nil, err
+		}
+		policy.quietHours = quietHours
+	}
+
+	return policy, nil
+}
+
+// AlertResult is the result of applying an alert policy to ticket listings.
+type AlertResult struct {
+	// Alerts are the listings that should be alerted.
+	Alerts []twigots.TicketListing
+	// Summary are the listings held during quiet hours, that should be alerted as a summary.
+	// This is set after quiet hours end, until the summary is committed. See Decide.
+	Summary []twigots.TicketListing
+	// Held are the listings that should be held during quiet hours. These are also in Suppressed.
+	// Held listings are only released as a summary once recorded as held. See Decide.
+	Held []twigots.TicketListing
+	// Suppressed are the listings that should not be alerted (or have been held), with the reason why.
+	Suppressed []Suppression
+}
+
+// Suppression is a listing that was not alerted, and the reason why.
+type Suppression struct {
+	Listing twigots.TicketListing
+	Reason  string
+}
+
+// Apply applies the policy to matched ticket listings, deciding which should be alerted, and recording them as
+// alerted (or held). This is the same as calling Decide, then Commit, CommitSummary and CommitHeld with the
+// listings decided.
+//
+// Apply should be called regularly (e.g. on every poll for new listings, even if there are none),
+// so listings held during quiet hours are released as a summary soon after quiet hours end.
+func (p *AlertPolicy) Apply(listings ...twigots.TicketListing) AlertResult {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := timeNow()
+	result := p.decideAll(listings, now)
+	p.commitSummary(result.Summary, now)
+	p.commitHeld(result.Held)
+	for _, listing := range result.Alerts {
+		p.commit(listing, now)
+	}
+	return result
+}
+
+// Decide decides which matched ticket listings should be alerted (or held), without recording them as alerted
+// (or held). Listings are decided in order, as if the listings decided to be alerted (or held) before them had been.
+//
+// Once listings have been alerted (e.g. sent successfully), they should be recorded as alerted using Commit,
+// and the summary using CommitSummary. Listings not committed will be decided again by the next call to Decide,
+// so failed alerts can be retried. If the summary is not committed, the listings are kept held, and released
+// again by the next call to Decide. Listings decided to be held during quiet hours should be recorded as held
+// using CommitHeld.
+//
+// Listings decided by concurrent calls to Decide are decided independently of each other, so the caller must
+// ensure listings are committed before Decide is called again if the policy limits must not be exceeded.
+//
+// Decide should be called regularly (e.g. on every poll for new listings, even if there are none),
+// so listings held during quiet hours are released as a summary soon after quiet hours end.
+func (p *AlertPolicy) Decide(listings ...twigots.TicketListing) AlertResult {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	return p.decideAll(listings, timeNow())
+}
+
+// Commit records ticket listings as alerted. See Decide.
+func (p *AlertPolicy) Commit(listings ...twigots.TicketListing) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := timeNow()
+	for _, listing := range listings {
+		p.commit(listing, now)
+	}
+}
+
+// CommitHeld records ticket listings as held during quiet hours, so they are released as a summary after quiet hours
+// end. If a listing is already held, it is replaced. See Decide.
+func (p *AlertPolicy) CommitHeld(listings ...twigots.TicketListing) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.commitHeld(listings)
+}
+
+// CommitSummary records the summary of listings held during quiet hours as alerted,
+// so the listings are no longer held. See Decide.
+func (p *AlertPolicy) CommitSummary(summary ...twigots.TicketListing) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.commitSummary(summary, timeNow())
+}
+
+// decideAll decides which listings should be alerted, or held during quiet hours.
+// The mutex must be held by the caller.
+func (p *AlertPolicy) decideAll(listings []twigots.TicketListing, now time.Time) AlertResult {
+	p.pruneAlertTimes(now)
+
+	// Listings are decided as if earlier listings had been alerted (or held), without recording them
+	state := p.state.clone()
+
+	var result AlertResult
+	isQuiet := p.quietHours != nil && p.quietHours.contains(now)
+	if !isQuiet && len(state.held) > 0 {
+		result.Summary = slices.Clone(state.held)
+		// The summary is a single alert
+		for _, listing := range result.Summary {
+			p.recordAlert(state, listing, now)
+		}
+		p.countAlert(state, now)
+	}
+
+	for _, listing := range listings {
+		reason, ok := p.decide(state, listing, now)
+		if !ok {
+			result.Suppressed = append(result.Suppressed, Suppression{Listing: listing, Reason: reason})
+			continue
+		}
+
+		if isQuiet {
+			hold(state, listing)
+			result.Held = append(result.Held, listing)
+			result.Suppressed = append(result.Suppressed, Suppression{
+				Listing: listing,
+				Reason:  fmt.Sprintf("held during quiet hours %s", p.quietHours),
+			})
+			continue
+		}
+
+		result.Alerts = append(result.Alerts, listing)
+		p.recordAlert(state, listing, now)
+		p.countAlert(state, now)
+	}
+
+	return result
+}
+
+// commit records a listing as alerted. The mutex must be held by the caller.
+func (p *AlertPolicy) commit(listing twigots.TicketListing, now time.Time) {
+	p.recordAlert(p.state, listing, now)
+	p.countAlert(p.state, now)
+}
+
+// commitSummary records a summary of held listings as a single alert, and stops holding the listings.
+// The mutex must be held by the caller.
+func (p *AlertPolicy) commitSummary(summary []twigots.TicketListing, now time.Time) {
+	if len(summary) == 0 {
+		return
+	}
+
+	for _, listing := range summary {
+		p.state.held = slices.DeleteFunc(p.state.held, func(heldListing twigots.TicketListing) bool {
+			return heldListing.Id == listing.Id
+		})
+		p.recordAlert(p.state, listing, now)
+	}
+	p.countAlert(p.state, now)
+}
+
+// commitHeld records listings as held. The mutex must be held by the caller.
+func (p *AlertPolicy) commitHeld(listings []twigots.TicketListing) {
+	for _, listing := range listings {
+		hold(p.state, listing)
+	}
+}
+
+// decide decides whether a listing should be alerted, returning the reason if not.
+func (p *AlertPolicy) decide(state *alertState, listing twigots.TicketListing, now time.Time) (string, bool) {
+	key := eventKey(listing)
+	lastAlert, hasLastAlert := state.alerts[key]
+	if _, ok := state.alertedListings[alertedListing{eventKey: key, listingId: listing.Id}]; ok {
+		return fmt.Sprintf("listing '%s' has already been alerted", listing.Id), false
+	}
+
+	if p.onlyCheaper {
+		lastPrice, ok := lastPrice(state, key)
+		if ok {
+			price := listing.TicketPriceInclFee()
+			cmp, err := price.Cmp(lastPrice)
+			// Listings in a different currency cannot be compared, so are alerted
+			if err == nil && cmp >= 0 {
+				return fmt.Sprintf(
+					"ticket price incl fee %s is not cheaper than last alerted %s", price, lastPrice,
+				), false
+			}
+		}
+	}
+
+	if p.cooldown > 0 {
+		var lastAlertedAt time.Time
+		if p.cooldownScope == CooldownPerWatch {
+			lastAlertedAt = state.lastAlertedAt
+		} else if hasLastAlert {
+			lastAlertedAt = lastAlert.at
+		}
+
+		cooldownEnd := lastAlertedAt.Add(p.cooldown)
+		if !lastAlertedAt.IsZero() && now.Before(cooldownEnd) {
+			if p.cooldownScope == CooldownPerWatch {
+				return fmt.Sprintf("in cooldown for %s", cooldownEnd.Sub(now).Round(time.Second)), false
+			}
+			return fmt.Sprintf(
+				"event '%s' in cooldown for %s", listing.Event.Name, cooldownEnd.Sub(now).Round(time.Second),
+			), false
+		}
+	}
+
+	if p.maxAlerts > 0 && len(state.alertTimes) >= p.maxAlerts {
+		return fmt.Sprintf("max %d alerts per %s reached", p.maxAlerts, p.maxAlertsWindow), false
+	}
+
+	return "", true
+}
+
+// lastPrice gets the price of the last listing alerted or held for an event.
+func lastPrice(state *alertState, key string) (twigots.Price, bool) {
+	// Held listings will be alerted, so should be compared against too
+	for idx := len(state.held) - 1; idx >= 0; idx-- {
+		if eventKey(state.held[idx]) == key {
+			return state.held[idx].TicketPriceInclFee(), true
+		}
+	}
+
+	lastAlert, ok := state.alerts[key]
+	if !ok {
+		return twigots.Price{}, false
+	}
+	return lastAlert.price, true
+}
+
+// hold holds a listing during quiet hours. If the listing is already held, it is replaced.
+func hold(state *alertState, listing twigots.TicketListing) {
+	idx := slices.IndexFunc(state.held, func(heldListing twigots.TicketListing) bool {
+		return heldListing.Id == listing.Id
+	})
+	if idx >= 0 {
+		state.held[idx] = listing
+		return
+	}
+	state.held = append(state.held, listing)
+}
+
+// recordAlert records a listing as alerted, and as the last alerted listing of its event.
+func (p *AlertPolicy) recordAlert(state *alertState, listing twigots.TicketListing, now time.Time) {
+	key := eventKey(listing)
+	state.alerts[key] = eventAlert{
+		at:    now,
+		price: listing.TicketPriceInclFee(),
+	}
+	state.alertedListings[alertedListing{eventKey: key, listingId: listing.Id}] = struct{}{}
+}
+
+// countAlert counts an alert towards the cooldown (per watch) and max alerts.
+func (p *AlertPolicy) countAlert(state *alertState, now time.Time) {
+	state.lastAlertedAt = now
+	if p.maxAlerts > 0 {
+		state.alertTimes = append(state.alertTimes, now)
+	}
+}
+
+// pruneAlertTimes removes the times of alerts before the max alerts window.
+func (p *AlertPolicy) pruneAlertTimes(now time.Time) {
+	idx := 0
+	for idx < len(p.state.alertTimes) && now.Sub(p.state.alertTimes[idx]) >= p.maxAlertsWindow {
+		idx++
+	}
+	p.state.alertTimes = p.state.alertTimes[idx:]
+}
+
+// quietHours are the hours of the day during which alerts are held.
+type quietHours struct {
+	// start and end are the offsets from midnight
+	start    time.Duration
+	end      time.Duration
+	location *time.Location
+}
+
+func newQuietHours(start, end string, location *time.Location) (*quietHours, error) {
+	startTime, err := time.Parse("15:04", start)
+	if err != nil {
+		return nil, fmt.Errorf("quiet hours start '%s' is not valid", start)
+	}
+	endTime, err := time.Parse("15:04", end)
+	if err != nil {
+		return nil, fmt.Errorf("quiet hours end '%s' is not valid", end)
+	}
+	if location == nil {
+		return nil, errors.New("quiet hours location is not set")
+	}
+
+	return &quietHours{
+		start:    time.Duration(startTime.Hour())*time.Hour + time.Duration(startTime.Minute())*time.Minute,
+		end:      time.Duration(endTime.Hour())*time.Hour + time.Duration(endTime.Minute())*time.Minute,
+		location: location,
+	}, nil
+}
+
+// contains returns whether a time is during quiet hours.
+func (q *quietHours) contains(t time.Time) bool {
+	t = t.In(q.location)
+	timeOfDay := time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute +
+		time.Duration(t.Second())*time.Second
+
+	if q.start <= q.end {
+		return timeOfDay >= q.start && timeOfDay < q.end
+	}
+	// Quiet hours span midnight
+	return timeOfDay >= q.start || timeOfDay < q.end
+}
+
+func (q *quietHours) String() string {
+	format := func(offset time.Duration) string {
+		return fmt.Sprintf("%02d:%02d", int(offset.Hours()), int(offset.Minutes())%60)
+	}
+	return fmt.Sprintf("%s-%s %s", format(q.start), format(q.end), q.location)
+}
@@ -0,0 +1,389 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+// setTime sets the current time, returning a function to advance it.
+func setTime(t *testing.T, now time.Time) func(time.Duration) {
+	timeNow = func() time.Time { return now }
+	t.Cleanup(func() { timeNow = time.Now })
+	return func(duration time.Duration) {
+		now = now.Add(duration)
+	}
+}
+
+func listingIds(listings []twigots.TicketListing) []string {
+	ids := make([]string, 0, len(listings))
+	for _, listing := range listings {
+		ids = append(ids, listing.Id)
+	}
+	return ids
+}
+
+func messageListingIds(messages []Message) []string {
+	ids := make([]string, 0, len(messages))
+	for _, message := range messages {
+		ids = append(ids, message.ListingId)
+	}
+	return ids
+}
+
+func TestAlertPolicyDefault(t *testing.T) {
+	policy, err := NewAlertPolicy()
+	require.NoError(t, err)
+
+	result := policy.Apply(testListing("1", "oasis", 100), testListing("2", "oasis", 120))
+	require.Equal(t, []string{"1", "2"}, listingIds(result.Alerts))
+
+	// Listings should only be alerted once
+	result = policy.Apply(testListing("2", "oasis", 120))
+	require.Empty(t, result.Alerts)
+	require.Equal(t, "listing '2' has already been alerted", result.Suppressed[0].Reason)
+}
+
+func TestAlertPolicyCooldownPerEvent(t *testing.T) {
+	advance := setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithCooldown(time.Hour, CooldownPerEvent))
+	require.NoError(t, err)
+
+	result := policy.Apply(
+		testListing("1", "oasis", 100),
+		testListing("2", "oasis", 100),
+		testListing("3", "blur", 100),
+	)
+	require.Equal(t, []string{"1", "3"}, listingIds(result.Alerts))
+	require.Len(t, result.Suppressed, 1)
+	require.Equal(t, "event 'oasis' in cooldown for 1h0m0s", result.Suppressed[0].Reason)
+
+	advance(59 * time.Minute)
+	result = policy.Apply(testListing("4", "oasis", 100))
+	require.Empty(t, result.Alerts)
+	require.Equal(t, "event 'oasis' in cooldown for 1m0s", result.Suppressed[0].Reason)
+
+	advance(time.Minute)
+	result = policy.Apply(testListing("4", "oasis", 100))
+	require.Equal(t, []string{"4"}, listingIds(result.Alerts))
+}
+
+func TestAlertPolicyCooldownPerWatch(t *testing.T) {
+	advance := setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithCooldown(10*time.Minute, CooldownPerWatch))
+	require.NoError(t, err)
+
+	result := policy.Apply(testListing("1", "oasis", 100), testListing("2", "blur", 100))
+	require.Equal(t, []string{"1"}, listingIds(result.Alerts))
+	require.Equal(t, "in cooldown for 10m0s", result.Suppressed[0].Reason)
+
+	advance(10 * time.Minute)
+	result = policy.Apply(testListing("2", "blur", 100))
+	require.Equal(t, []string{"2"}, listingIds(result.Alerts))
+}
+
+func TestAlertPolicyMaxAlerts(t *testing.T) {
+	advance := setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithMaxAlerts(2, time.Hour))
+	require.NoError(t, err)
+
+	result := policy.Apply(testListing("1", "a", 100), testListing("2", "b", 100), testListing("3", "c", 100))
+	require.Equal(t, []string{"1", "2"}, listingIds(result.Alerts))
+	require.Equal(t, "max 2 alerts per 1h0m0s reached", result.Suppressed[0].Reason)
+
+	// Window is sliding, so alerts are allowed again as earlier alerts leave the window
+	advance(30 * time.Minute)
+	result = policy.Apply(testListing("3", "c", 100))
+	require.Empty(t, result.Alerts)
+
+	advance(30 * time.Minute)
+	result = policy.Apply(testListing("3", "c", 100), testListing("4", "d", 100), testListing("5", "e", 100))
+	require.Equal(t, []string{"3", "4"}, listingIds(result.Alerts))
+}
+
+func TestAlertPolicyAlreadyAlerted(t *testing.T) {
+	setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithMaxAlerts(3, time.Hour))
+	require.NoError(t, err)
+
+	result := policy.Apply(testListing("1", "oasis", 100), testListing("2", "oasis", 100))
+	require.Equal(t, []string{"1", "2"}, listingIds(result.Alerts))
+
+	// All listings alerted for an event should be remembered, not just the last, so they do not use up max alerts
+	result = policy.Apply(testListing("1", "oasis", 100), testListing("2", "oasis", 100))
+	require.Empty(t, result.Alerts)
+	require.Equal(t, "listing '1' has already been alerted", result.Suppressed[0].Reason)
+	require.Equal(t, "listing '2' has already been alerted", result.Suppressed[1].Reason)
+
+	result = policy.Apply(testListing("3", "oasis", 100))
+	require.Equal(t, []string{"3"}, listingIds(result.Alerts))
+}
+
+func TestAlertPolicyOnlyCheaper(t *testing.T) {
+	policy, err := NewAlertPolicy(WithOnlyCheaper())
+	require.NoError(t, err)
+
+	result := policy.Apply(
+		testListing("1", "oasis", 100),
+		testListing("2", "oasis", 100),
+		testListing("3", "oasis", 90),
+		testListing("4", "blur", 200),
+		testListing("5", "oasis", 95),
+	)
+	require.Equal(t, []string{"1", "3", "4"}, listingIds(result.Alerts))
+	require.Len(t, result.Suppressed, 2)
+	require.Equal(t,
+		"ticket price incl fee £60.00 is not cheaper than last alerted £60.00",
+		result.Suppressed[0].Reason,
+	)
+	require.Equal(t,
+		"ticket price incl fee £57.50 is not cheaper than last alerted £55.00",
+		result.Suppressed[1].Reason,
+	)
+
+	// Listings in another currency cannot be compared, so should be alerted
+	euroListing := testListing("6", "oasis", 200)
+	euroListing.TotalPriceExclFee.Currency = twigots.CurrencyEUR
+	euroListing.TwicketsFee.Currency = twigots.CurrencyEUR
+	result = policy.Apply(euroListing)
+	require.Equal(t, []string{"6"}, listingIds(result.Alerts))
+}
+
+func TestAlertPolicyQuietHours(t *testing.T) {
+	london, err := time.LoadLocation("Europe/London")
+	require.NoError(t, err)
+
+	// 22:30 in London (BST)
+	advance := setTime(t, time.Date(2025, 7, 1, 22, 30, 0, 0, london))
+	policy, err := NewAlertPolicy(
+		WithQuietHours("23:00", "07:00", london),
+		WithOnlyCheaper(),
+		WithMaxAlerts(2, time.Hour),
+	)
+	require.NoError(t, err)
+
+	result := policy.Apply(testListing("1", "oasis", 100))
+	require.Equal(t, []string{"1"}, listingIds(result.Alerts))
+
+	// Listings should be held during quiet hours, still subject to the only cheaper rule
+	advance(time.Hour)
+	result = policy.Apply(
+		testListing("2", "oasis", 90),
+		testListing("3", "oasis", 95),
+		testListing("4", "blur", 100),
+		testListing("5", "coldplay", 100),
+	)
+	require.Empty(t, result.Alerts)
+	require.Empty(t, result.Summary)
+	require.Len(t, result.Suppressed, 4)
+	require.Equal(t, "held during quiet hours 23:00-07:00 Europe/London", result.Suppressed[0].Reason)
+	require.Equal(t,
+		"ticket price incl fee £57.50 is not cheaper than last alerted £55.00",
+		result.Suppressed[1].Reason,
+	)
+
+	advance(7 * time.Hour)
+	result = policy.Apply()
+	require.Empty(t, result.Summary)
+
+	// Held listings should be released as a summary after quiet hours
+	advance(time.Hour)
+	result = policy.Apply(testListing("6", "oasis", 85), testListing("7", "oasis", 90))
+	require.Equal(t, []string{"2", "4", "5"}, listingIds(result.Summary))
+	require.Equal(t, []string{"6"}, listingIds(result.Alerts))
+
+	result = policy.Apply()
+	require.Empty(t, result.Summary)
+}
+
+func TestNewAlertPolicyInvalid(t *testing.T) {
+	_, err := NewAlertPolicy(WithQuietHours("23:00", "7am", time.UTC))
+	require.EqualError(t, err, "quiet hours end '7am' is not valid")
+
+	_, err = NewAlertPolicy(WithQuietHours("23:00", "07:00", nil))
+	require.Error(t, err)
+
+	_, err = NewAlertPolicy(WithMaxAlerts(5, 0))
+	require.Error(t, err)
+
+	_, err = NewAlertPolicy(WithCooldown(-time.Minute, CooldownPerEvent))
+	require.Error(t, err)
+}
+
+func TestDispatcherWithPolicy(t *testing.T) {
+	advance := setTime(t, time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC))
+	policy, err := NewAlertPolicy(WithQuietHours("23:00", "07:00", time.UTC))
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithPolicy(policy))
+	require.NoError(t, err)
+
+	err = dispatcher.Dispatch(context.Background(), testListing("1", "oasis", 100), testListing("2", "blur", 100))
+	require.NoError(t, err)
+	require.Empty(t, notifier.messages)
+
+	advance(8 * time.Hour)
+	err = dispatcher.Dispatch(context.Background(), testListing("3", "coldplay", 100))
+	require.NoError(t, err)
+
+	require.Len(t, notifier.messages, 2)
+	require.Equal(t, Message{
+		Title: "2 ticket listing(s) during quiet hours",
+		Body: "- oasis: https://www.twickets.live/app/block/1,2\n" +
+			"- blur: https://www.twickets.live/app/block/2,2",
+	}, notifier.messages[0])
+	require.Equal(t, "3", notifier.messages[1].ListingId)
+}
+
+func TestAlertPolicyDecideAndCommit(t *testing.T) {
+	advance := setTime(t, time.Date(2025, 7, 1, 22, 30, 0, 0, time.UTC))
+	policy, err := NewAlertPolicy(WithCooldown(time.Hour, CooldownPerEvent), WithQuietHours("23:00", "07:00", time.UTC))
+	require.NoError(t, err)
+
+	// Listings should be decided as if earlier listings had been alerted, but not be recorded as alerted
+	result := policy.Decide(testListing("1", "oasis", 100), testListing("2", "oasis", 100))
+	require.Equal(t, []string{"1"}, listingIds(result.Alerts))
+	require.Equal(t, "event 'oasis' in cooldown for 1h0m0s", result.Suppressed[0].Reason)
+
+	result = policy.Decide(testListing("1", "oasis", 100))
+	require.Equal(t, []string{"1"}, listingIds(result.Alerts))
+
+	policy.Commit(result.Alerts...)
+	result = policy.Decide(testListing("1", "oasis", 100))
+	require.Empty(t, result.Alerts)
+
+	// Held listings should only be released once committed, and then again until the summary is committed
+	advance(time.Hour)
+	held := policy.Decide(testListing("3", "blur", 100)).Held
+	require.Equal(t, []string{"3"}, listingIds(held))
+
+	advance(8 * time.Hour)
+	require.Empty(t, policy.Decide().Summary)
+
+	policy.CommitHeld(held...)
+	result = policy.Decide()
+	require.Equal(t, []string{"3"}, listingIds(result.Summary))
+	result = policy.Decide()
+	require.Equal(t, []string{"3"}, listingIds(result.Summary))
+
+	policy.CommitSummary(result.Summary...)
+	result = policy.Decide(testListing("4", "blur", 100))
+	require.Empty(t, result.Summary)
+	require.Empty(t, result.Alerts)
+	require.Equal(t, "event 'blur' in cooldown for 1h0m0s", result.Suppressed[0].Reason)
+}
+
+func TestDispatcherWithPolicyFailures(t *testing.T) {
+	advance := setTime(t, time.Date(2025, 7, 1, 22, 30, 0, 0, time.UTC))
+	policy, err := NewAlertPolicy(WithCooldown(time.Hour, CooldownPerEvent), WithQuietHours("23:00", "07:00", time.UTC))
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{failures: 1}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithPolicy(policy), WithRetry(1, 0))
+	require.NoError(t, err)
+
+	// Listings that fail to send should be sent when dispatched again
+	err = dispatcher.Dispatch(context.Background(), testListing("1", "oasis", 100))
+	require.Error(t, err)
+	require.Empty(t, notifier.messages)
+
+	err = dispatcher.Dispatch(context.Background(), testListing("1", "oasis", 100))
+	require.NoError(t, err)
+	require.Len(t, notifier.messages, 1)
+	require.Equal(t, "1", notifier.messages[0].ListingId)
+
+	// Held listings should be sent again if the summary fails to send
+	advance(time.Hour)
+	err = dispatcher.Dispatch(context.Background(), testListing("2", "blur", 100))
+	require.NoError(t, err)
+
+	advance(8 * time.Hour)
+	notifier.failures = 1
+	err = dispatcher.Dispatch(context.Background())
+	require.Error(t, err)
+	require.Len(t, notifier.messages, 1)
+
+	err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, notifier.messages, 2)
+	require.Equal(t, "1 ticket listing(s) during quiet hours", notifier.messages[1].Title)
+
+	err = dispatcher.Dispatch(context.Background())
+	require.NoError(t, err)
+	require.Len(t, notifier.messages, 2)
+}
+
+func TestDispatcherWithPolicyDuplicates(t *testing.T) {
+	setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithMaxAlerts(3, time.Hour))
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithPolicy(policy))
+	require.NoError(t, err)
+
+	// Listings already sent to all sinks should not be recorded as alerted again
+	_, err = dispatcher.Send(context.Background(), Message{ListingId: "1"})
+	require.NoError(t, err)
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "oasis", 100)))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("2", "oasis", 100)))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("2", "oasis", 100)))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("3", "oasis", 100)))
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("4", "oasis", 100)))
+	require.Equal(t, []string{"1", "2", "3", "4"}, messageListingIds(notifier.messages))
+}
+
+func TestDispatcherWithPolicyConcurrent(t *testing.T) {
+	setTime(t, time.Now())
+	policy, err := NewAlertPolicy(WithMaxAlerts(3, time.Hour))
+	require.NoError(t, err)
+
+	notifier := &recordingNotifier{}
+	dispatcher, err := NewDispatcher(WithSink("sink", notifier), WithPolicy(policy))
+	require.NoError(t, err)
+
+	// Concurrent dispatches should not exceed the max alerts
+	var waitGroup sync.WaitGroup
+	errs := make([]error, 10)
+	for idx := range errs {
+		waitGroup.Add(1)
+		go func() {
+			defer waitGroup.Done()
+			errs[idx] = dispatcher.Dispatch(context.Background(), testListing(strconv.Itoa(idx), "oasis", 100))
+		}()
+	}
+	waitGroup.Wait()
+	require.NoError(t, errors.Join(errs...))
+	require.Len(t, notifier.messages, 3)
+}
+
+func TestDispatcherWithPolicySummaryPartialFailure(t *testing.T) {
+	advance := setTime(t, time.Date(2025, 7, 1, 23, 30, 0, 0, time.UTC))
+	policy, err := NewAlertPolicy(WithQuietHours("23:00", "07:00", time.UTC))
+	require.NoError(t, err)
+
+	first := &recordingNotifier{}
+	second := &recordingNotifier{failures: 1}
+	dispatcher, err := NewDispatcher(
+		WithSink("first", first), WithSink("second", second), WithPolicy(policy), WithRetry(1, 0),
+	)
+	require.NoError(t, err)
+
+	require.NoError(t, dispatcher.Dispatch(context.Background(), testListing("1", "oasis", 100)))
+
+	// A summary that fails to send to a sink should only be sent again to that sink
+	advance(8 * time.Hour)
+	require.Error(t, dispatcher.Dispatch(context.Background()))
+	require.NoError(t, dispatcher.Dispatch(context.Background()))
+	require.NoError(t, dispatcher.Dispatch(context.Background()))
+	require.Len(t, first.messages, 1)
+	require.Len(t, second.messages, 1)
+	require.Equal(t, "1 ticket listing(s) during quiet hours", second.messages[0].Title)
+}
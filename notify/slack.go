@@ -0,0 +1,92 @@
+package notify
+
+import (
+	"context"
+	"strings"
+)
+
+// Slack limits the length of block text.
+// See https://api.slack.com/reference/block-kit/blocks
+const (
+	slackMaxHeaderLength  = 150
+	slackMaxSectionLength = 3000
+)
+
+// SlackNotifier is a notifier that posts messages to a Slack incoming webhook using Block Kit.
+//
+// Each message is posted as a header with the message title, a section with the message body,
+// and a "Buy" button linking to the ticket listing.
+type SlackNotifier struct {
+	webhookURL string
+	options    httpOptions
+}
+
+// NewSlackNotifier creates a notifier that posts messages to a Slack incoming webhook url.
+// See https://api.slack.com/messaging/webhooks
+func NewSlackNotifier(webhookURL string, opts ...HTTPOpt) *SlackNotifier {
+	return &SlackNotifier{
+		webhookURL: webhookURL,
+		options:    newHTTPOptions("", opts),
+	}
+}
+
+type slackWebhookBody struct {
+	// Text is the fallback text shown in notifications.
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type     string         `json:"type"`
+	Text     *slackText     `json:"text,omitempty"`
+	Elements []slackElement `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type  string    `json:"type"`
+	Text  slackText `json:"text"`
+	URL   string    `json:"url"`
+	Style string    `json:"style,omitempty"`
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, message Message) error {
+	blocks := []slackBlock{
+		{
+			Type: "header",
+			Text: &slackText{Type: "plain_text", Text: truncate(message.Title, slackMaxHeaderLength)},
+		},
+		{
+			Type: "section",
+			Text: &slackText{Type: "mrkdwn", Text: truncate(escapeSlackText(message.Body), slackMaxSectionLength)},
+		},
+	}
+
+	url := message.listingURL()
+	if url != "" {
+		blocks = append(blocks, slackBlock{
+			Type: "actions",
+			Elements: []slackElement{{
+				Type:  "button",
+				Text:  slackText{Type: "plain_text", Text: "Buy"},
+				URL:   url,
+				Style: "primary",
+			}},
+		})
+	}
+
+	return n.options.postJSON(ctx, n.webhookURL, slackWebhookBody{
+		Text:   message.Title,
+		Blocks: blocks,
+	})
+}
+
+// escapeSlackText escapes the characters Slack uses for formatting controls.
+// See https://api.slack.com/reference/surfaces/formatting#escaping
+func escapeSlackText(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
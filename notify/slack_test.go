@@ -0,0 +1,56 @@
+package notify
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlackNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewSlackNotifier(server.URL + "/services/T/B/X")
+	err := notifier.Notify(context.Background(), Message{
+		Title: "Oasis",
+		Body:  "Tickets <cheap> & cheerful",
+		URL:   "https://www.twickets.live/app/block/123,2",
+	})
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/services/T/B/X", request.Path)
+	require.Equal(t, map[string]any{
+		"text": "Oasis",
+		"blocks": []any{
+			map[string]any{
+				"type": "header",
+				"text": map[string]any{"type": "plain_text", "text": "Oasis"},
+			},
+			map[string]any{
+				"type": "section",
+				"text": map[string]any{"type": "mrkdwn", "text": "Tickets &lt;cheap&gt; &amp; cheerful"},
+			},
+			map[string]any{
+				"type": "actions",
+				"elements": []any{map[string]any{
+					"type":  "button",
+					"text":  map[string]any{"type": "plain_text", "text": "Buy"},
+					"url":   "https://www.twickets.live/app/block/123,2",
+					"style": "primary",
+				}},
+			},
+		},
+	}, request.Body)
+}
+
+func TestSlackNotifierWithoutURL(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewSlackNotifier(server.URL)
+	err := notifier.Notify(context.Background(), Message{Title: "Oasis", Body: "Tickets"})
+	require.NoError(t, err)
+
+	// No buy button should be added
+	require.Len(t, server.request(t).Body["blocks"], 2)
+}
@@ -0,0 +1,82 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"html"
+)
+
+// DefaultTelegramBaseURL is the default base url of the Telegram bot API.
+const DefaultTelegramBaseURL = "https://api.telegram.org"
+
+// telegramMaxTextLength is the maximum length of a Telegram message.
+const telegramMaxTextLength = 4096
+
+// TelegramNotifier is a notifier that sends messages to a Telegram chat using a bot.
+//
+// Each message is sent as HTML, with the message title in bold followed by the message body,
+// and an inline "Buy" button linking to the ticket listing.
+type TelegramNotifier struct {
+	botToken string
+	chatId   string
+	options  httpOptions
+}
+
+// NewTelegramNotifier creates a notifier that sends messages to a Telegram chat using a bot.
+// The chat id can be the id of a chat, or the username of a channel e.g. @channelusername.
+// See https://core.telegram.org/bots/api#sendmessage
+//
+// Messages are sent to DefaultTelegramBaseURL, unless set using WithBaseURL.
+func NewTelegramNotifier(botToken, chatId string, opts ...HTTPOpt) *TelegramNotifier {
+	return &TelegramNotifier{
+		botToken: botToken,
+		chatId:   chatId,
+		options:  newHTTPOptions(DefaultTelegramBaseURL, opts),
+	}
+}
+
+type telegramSendMessageBody struct {
+	ChatId             string                `json:"chat_id"`
+	Text               string                `json:"text"`
+	ParseMode          string                `json:"parse_mode"`
+	LinkPreviewOptions telegramLinkPreview   `json:"link_preview_options"`
+	ReplyMarkup        *telegramInlineMarkup `json:"reply_markup,omitempty"`
+}
+
+type telegramLinkPreview struct {
+	IsDisabled bool `json:"is_disabled"`
+}
+
+type telegramInlineMarkup struct {
+	InlineKeyboard [][]telegramInlineButton `json:"inline_keyboard"`
+}
+
+type telegramInlineButton struct {
+	Text string `json:"text"`
+	URL  string `json:"url"`
+}
+
+func (n *TelegramNotifier) Notify(ctx context.Context, message Message) error {
+	// Telegram limits the length of the text after HTML is parsed, so truncate before escaping
+	title := truncate(message.Title, telegramMaxTextLength)
+	messageBody := truncate(message.Body, max(telegramMaxTextLength-len([]rune(title))-1, 1))
+	text := fmt.Sprintf("<b>%s</b>\n%s", html.EscapeString(title), html.EscapeString(messageBody))
+
+	body := telegramSendMessageBody{
+		ChatId:    n.chatId,
+		Text:      text,
+		ParseMode: "HTML",
+		// The buy button links to the listing, so a link preview is not needed
+		LinkPreviewOptions: telegramLinkPreview{IsDisabled: true},
+	}
+
+	url := message.listingURL()
+	if url != "" {
+		body.ReplyMarkup = &telegramInlineMarkup{
+			InlineKeyboard: [][]telegramInlineButton{{{Text: "Buy", URL: url}}},
+		}
+	}
+
+	requestURL := fmt.Sprintf("%s/bot%s/sendMessage", n.options.baseURL, n.botToken)
+	return n.options.postJSON(ctx, requestURL, body)
+}
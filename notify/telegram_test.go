@@ -0,0 +1,51 @@
+package notify
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestTelegramNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	listing := testListing("123", "Oasis", 160)
+	notifier := NewTelegramNotifier("bot-token", "@channel", WithBaseURL(server.URL+"/"))
+	err := notifier.Notify(context.Background(), Message{
+		Title:   "Oasis & Friends",
+		Body:    "2 ticket(s) <cheap>",
+		Listing: listing,
+	})
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/botbot-token/sendMessage", request.Path)
+	require.Equal(t, map[string]any{
+		"chat_id":              "@channel",
+		"text":                 "<b>Oasis &amp; Friends</b>\n2 ticket(s) &lt;cheap&gt;",
+		"parse_mode":           "HTML",
+		"link_preview_options": map[string]any{"is_disabled": true},
+		"reply_markup": map[string]any{
+			"inline_keyboard": []any{[]any{map[string]any{
+				"text": "Buy",
+				"url":  "https://www.twickets.live/app/block/123,2",
+			}}},
+		},
+	}, request.Body)
+}
+
+func TestTelegramNotifierTruncates(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewTelegramNotifier("token", "1", WithBaseURL(server.URL))
+	err := notifier.Notify(context.Background(), Message{Title: "Title", Body: strings.Repeat("&", 5000)})
+	require.NoError(t, err)
+
+	// Length should be limited before escaping
+	body := server.request(t).Body
+	expectedText := "<b>Title</b>\n" + strings.Repeat("&amp;", telegramMaxTextLength-7) + "…"
+	require.Equal(t, expectedText, body["text"])
+	require.NotContains(t, body, "reply_markup")
+}
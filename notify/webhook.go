@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"context"
+)
+
+// WebhookNotifier is a notifier that posts messages to a webhook as JSON.
+//
+// The JSON body is an object with the message title, body, url and listing id e.g.
+//
+//	{"title": "Oasis", "body": "2 ticket(s) at Wembley Stadium...", "url": "https://...", "listingId": "123"}
+type WebhookNotifier struct {
+	url     string
+	options httpOptions
+}
+
+// NewWebhookNotifier creates a notifier that posts messages to a webhook url.
+func NewWebhookNotifier(url string, opts ...HTTPOpt) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:     url,
+		options: newHTTPOptions("", opts),
+	}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, message Message) error {
+	return n.options.postJSON(ctx, n.url, message)
+}
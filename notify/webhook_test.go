@@ -0,0 +1,102 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+// recordedRequest is a request received by a recording server.
+type recordedRequest struct {
+	Path   string
+	Header http.Header
+	Body   map[string]any
+}
+
+// recordingServer is a test server that records the JSON requests it receives.
+type recordingServer struct {
+	*httptest.Server
+	mutex    sync.Mutex
+	requests []recordedRequest
+}
+
+func newRecordingServer(t *testing.T) *recordingServer {
+	server := &recordingServer{}
+	server.Server = httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		bodyBytes, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		var body map[string]any
+		err = json.Unmarshal(bodyBytes, &body)
+		require.NoError(t, err)
+
+		server.mutex.Lock()
+		defer server.mutex.Unlock()
+		server.requests = append(server.requests, recordedRequest{
+			Path:   r.URL.Path,
+			Header: r.Header,
+			Body:   body,
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// request gets the only request received by the server.
+func (s *recordingServer) request(t *testing.T) recordedRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	require.Len(t, s.requests, 1)
+	return s.requests[0]
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	server := newRecordingServer(t)
+
+	notifier := NewWebhookNotifier(server.URL+"/hook", WithHeader("Authorization", "Bearer token"))
+	message, err := DefaultRenderer().Render(testListing("123", "Oasis", 160))
+	require.NoError(t, err)
+
+	err = notifier.Notify(context.Background(), message)
+	require.NoError(t, err)
+
+	request := server.request(t)
+	require.Equal(t, "/hook", request.Path)
+	require.Equal(t, "application/json", request.Header.Get("Content-Type"))
+	require.Equal(t, "Bearer token", request.Header.Get("Authorization"))
+	require.Equal(t, map[string]any{
+		"title":     "Oasis",
+		"body":      message.Body,
+		"url":       "https://www.twickets.live/app/block/123,2",
+		"listingId": "123",
+	}, request.Body)
+}
+
+func TestWebhookNotifierError(t *testing.T) {
+	attempts := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		attempts++
+		if attempts < 3 {
+			http.Error(w, "try again later", http.StatusServiceUnavailable)
+		}
+	}))
+	defer server.Close()
+
+	notifier := NewWebhookNotifier(server.URL)
+	err := notifier.Notify(context.Background(), Message{})
+	require.EqualError(t, err, "request failed: 503 Service Unavailable: try again later")
+
+	// Dispatcher should retry until the webhook succeeds
+	dispatcher, err := NewDispatcher(WithSink("webhook", notifier), WithRetry(3, time.Millisecond))
+	require.NoError(t, err)
+	err = dispatcher.Dispatch(context.Background(), testListing("123", "Oasis", 160))
+	require.NoError(t, err)
+	require.Equal(t, 3, attempts)
+}
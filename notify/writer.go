@@ -0,0 +1,39 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+)
+
+// WriterNotifier is a notifier that writes messages to a writer e.g. stdout.
+// Messages are written as the title, followed by the body, separated from other messages by a blank line.
+//
+// A WriterNotifier is safe for concurrent use.
+type WriterNotifier struct {
+	mutex  sync.Mutex
+	writer io.Writer
+}
+
+// NewWriterNotifier creates a notifier that writes messages to a writer.
+func NewWriterNotifier(writer io.Writer) *WriterNotifier {
+	return &WriterNotifier{writer: writer}
+}
+
+// NewStdoutNotifier creates a notifier that writes messages to stdout.
+func NewStdoutNotifier() *WriterNotifier {
+	return NewWriterNotifier(os.Stdout)
+}
+
+func (n *WriterNotifier) Notify(_ context.Context, message Message) error {
+	n.mutex.Lock()
+	defer n.mutex.Unlock()
+
+	_, err := fmt.Fprintf(n.writer, "%s\n%s\n\n", message.Title, message.Body)
+	if err != nil {
+		return fmt.Errorf("failed to write message: %w", err)
+	}
+	return nil
+}
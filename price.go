@@ -2,50 +2,71 @@ package twigots
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
-	"math"
 	"strconv"
+	"strings"
 
 	"github.com/orsinium-labs/enum"
 )
 
+// ErrCurrencyMismatch is returned when doing arithmetic with, or comparing, prices in different currencies.
+var ErrCurrencyMismatch = errors.New("currencies do not match")
+
 type Price struct {
 	Currency Currency `json:"currencyCode"`
 
-	// Amount is the cost in Cents, Pennies etc.
-	// Prefer using `Number`
+	// Amount is the cost in the minor unit of the currency e.g. Cents, Pennies etc.
+	// The number of minor units in a major unit depends on the currency. See Currency.Exponent.
+	// Prefer using `Number` for display, and Amount (or Cmp) for calculations and comparisons.
 	Amount int `json:"amountInCents"`
 }
 
 // Number is the numerical value of the price.
 // E.g. Dollars, Pounds, Euros etc.
-// Use this over `Amount“.
+// Use this over `Amount“ for display. As this is a float, do not use it for calculations or comparisons.
 func (p Price) Number() float64 {
-	return float64(p.Amount) / 100
+	return float64(p.Amount) / float64(p.Currency.minorUnits())
 }
 
 // The price as a string.
-// e.g. $30.62
+// e.g. $30.62, or 30.62 CHF for currencies without a symbol.
+//
+// Use Format to format the price using the conventions of a locale.
 func (p Price) String() string {
-	return priceString(p.Number(), p.Currency)
+	return priceString(p.Amount, p.Currency)
 }
 
-// Add prices together. Currency will be kept.
-// Returns a new price.
-func (p Price) Add(other Price) Price {
+// Add prices together. Returns a new price.
+//
+// If the prices have different currencies, ErrCurrencyMismatch is returned.
+// A zero price with no currency can be added to a price of any currency.
+func (p Price) Add(other Price) (Price, error) {
+	currency, err := commonCurrency(p, other)
+	if err != nil {
+		return Price{}, err
+	}
+
 	return Price{
-		Currency: p.Currency,
+		Currency: currency,
 		Amount:   p.Amount + other.Amount,
-	}
+	}, nil
 }
 
-// Subtract price from another. Currency will be kept.
-// Returns a new price.
-func (p Price) Subtract(other Price) Price {
+// Subtract price from another. Returns a new price.
+//
+// If the prices have different currencies, ErrCurrencyMismatch is returned.
+// A zero price with no currency can be subtracted from a price of any currency.
+func (p Price) Subtract(other Price) (Price, error) {
+	currency, err := commonCurrency(p, other)
+	if err != nil {
+		return Price{}, err
+	}
+
 	return Price{
-		Currency: p.Currency,
+		Currency: currency,
 		Amount:   p.Amount - other.Amount,
-	}
+	}, nil
 }
 
 // Multiply prices together. Returns a new price.
@@ -57,28 +78,194 @@ func (p Price) Multiply(num int) Price {
 }
 
 // Divide prices. Currency will be kept.
+// Amount is rounded to the nearest minor unit, with halves rounded away from zero.
+// If num is 0, the returned price will have an amount of 0.
 // Returns a new price.
 func (p Price) Divide(num int) Price {
 	return Price{
 		Currency: p.Currency,
-		Amount:   int(math.Round(float64(p.Amount) / float64(num))),
+		Amount:   divideRound(p.Amount, num),
+	}
+}
+
+// Split splits a price into a number of parts, whose amounts add up to the amount of the price.
+// Any remainder is distributed one minor unit at a time to the first parts, so no two parts differ by more than
+// one minor unit e.g. £10.00 split into 3 parts is £3.34, £3.33 and £3.33.
+// If num is <=0, nil is returned.
+// Returns new prices.
+func (p Price) Split(num int) []Price {
+	if num <= 0 {
+		return nil
 	}
+
+	// Use truncated division, so the remainder has the same sign as the amount
+	quotient := p.Amount / num
+	remainder := p.Amount % num
+
+	parts := make([]Price, num)
+	for idx := 0; idx < num; idx++ {
+		amount := quotient
+		if idx < absInt(remainder) {
+			if remainder > 0 {
+				amount++
+			} else {
+				amount--
+			}
+		}
+
+		parts[idx] = Price{
+			Currency: p.Currency,
+			Amount:   amount,
+		}
+	}
+
+	return parts
 }
 
-func priceString(cost float64, currency Currency) string {
-	costString := strconv.FormatFloat(cost, 'f', 2, 64)
+// Cmp compares prices, returning -1 if the price is less than the other price,
+// 0 if they are equal and +1 if the price is greater than the other price.
+//
+// If the prices have different currencies, ErrCurrencyMismatch is returned.
+// A zero price with no currency can be compared with a price of any currency.
+func (p Price) Cmp(other Price) (int, error) {
+	_, err := commonCurrency(p, other)
+	if err != nil {
+		return 0, err
+	}
+
+	switch {
+	case p.Amount < other.Amount:
+		return -1, nil
+	case p.Amount > other.Amount:
+		return 1, nil
+	default:
+		return 0, nil
+	}
+}
+
+// Less returns whether the price is less than the other price.
+//
+// If the prices have different currencies, ErrCurrencyMismatch is returned.
+func (p Price) Less(other Price) (bool, error) {
+	cmp, err := p.Cmp(other)
+	if err != nil {
+		return false, err
+	}
+	return cmp < 0, nil
+}
+
+// Equal returns whether the prices have the same currency and amount.
+func (p Price) Equal(other Price) bool {
+	return p.Currency == other.Currency && p.Amount == other.Amount
+}
+
+// plus adds prices together, keeping the currency of the price.
+// This should only be used for prices that are known to have the same currency,
+// such as the prices of a single ticket listing.
+func (p Price) plus(other Price) Price {
+	return Price{
+		Currency: p.Currency,
+		Amount:   p.Amount + other.Amount,
+	}
+}
+
+// commonCurrency gets the currency shared by two prices, allowing for zero prices with no currency.
+func commonCurrency(price, other Price) (Currency, error) {
+	switch {
+	case price.Currency == other.Currency:
+		return price.Currency, nil
+	case other.Currency == (Currency{}) && other.Amount == 0:
+		return price.Currency, nil
+	case price.Currency == (Currency{}) && price.Amount == 0:
+		return other.Currency, nil
+	default:
+		return Currency{}, fmt.Errorf("%w: %s and %s", ErrCurrencyMismatch, price.Currency.Value, other.Currency.Value)
+	}
+}
+
+// divideRound divides integers, rounding to the nearest integer, with halves rounded away from zero.
+// If divisor is 0, 0 is returned.
+func divideRound(dividend, divisor int) int {
+	if divisor == 0 {
+		return 0
+	}
+
+	quotient := dividend / divisor
+	remainder := dividend % divisor
+	if remainder == 0 {
+		return quotient
+	}
+
+	// Round away from zero if the remainder is at least half the divisor
+	if 2*absInt(remainder) >= absInt(divisor) {
+		if (dividend < 0) == (divisor < 0) {
+			return quotient + 1
+		}
+		return quotient - 1
+	}
+
+	return quotient
+}
+
+func absInt(num int) int {
+	if num < 0 {
+		return -num
+	}
+	return num
+}
+
+func priceString(amount int, currency Currency) string {
+	costString := formatAmount(amount, currency.Exponent())
 	currencyString := currency.Symbol()
 	if currencyString == "" {
-		return costString + currency.Value
+		return costString + " " + currency.Value
 	}
 
+	// Put any negative sign before the symbol
+	if amount < 0 {
+		return "-" + currencyString + costString[1:]
+	}
 	return currencyString + costString
 }
 
+// formatAmount formats an amount in minor units as a decimal string with the number of decimal places specified
+// by exponent, without going through floats e.g. 3062 with an exponent of 2 is formatted as 30.62.
+func formatAmount(amount, exponent int) string {
+	sign := ""
+	if amount < 0 {
+		sign = "-"
+	}
+
+	digits := strconv.Itoa(absInt(amount))
+	if exponent <= 0 {
+		return sign + digits
+	}
+
+	// Pad with leading zeros so there is at least one digit before the decimal point
+	if len(digits) <= exponent {
+		digits = strings.Repeat("0", exponent-len(digits)+1) + digits
+	}
+
+	return sign + digits[:len(digits)-exponent] + "." + digits[len(digits)-exponent:]
+}
+
 var (
 	currency = enum.NewBuilder[string, Currency]()
 
 	CurrencyGBP = currency.Add(Currency{"GBP"})
+	CurrencyEUR = currency.Add(Currency{"EUR"})
+	CurrencyUSD = currency.Add(Currency{"USD"})
+	CurrencyCAD = currency.Add(Currency{"CAD"})
+	CurrencyAUD = currency.Add(Currency{"AUD"})
+	CurrencyCHF = currency.Add(Currency{"CHF"})
+	CurrencySEK = currency.Add(Currency{"SEK"})
+	CurrencyNOK = currency.Add(Currency{"NOK"})
+	CurrencyDKK = currency.Add(Currency{"DKK"})
+	CurrencyPLN = currency.Add(Currency{"PLN"})
+	CurrencyCZK = currency.Add(Currency{"CZK"})
+	CurrencyHUF = currency.Add(Currency{"HUF"})
+	CurrencyISK = currency.Add(Currency{"ISK"})
+	CurrencyJPY = currency.Add(Currency{"JPY"})
 
 	Currencies = currency.Enum()
 )
@@ -87,15 +274,45 @@ type Currency enum.Member[string]
 
 // Symbol is the character that represents the currency
 // e.g. $, £, €.
+//
+// Currencies without a widely recognised unique symbol return an empty string.
 func (c Currency) Symbol() string {
 	switch c {
 	case CurrencyGBP:
 		return "£"
+	case CurrencyEUR:
+		return "€"
+	case CurrencyUSD:
+		return "$"
+	case CurrencyJPY:
+		return "¥"
 	default:
 		return ""
 	}
 }
 
+// Exponent is the number of decimal places of the minor unit of the currency,
+// as defined by ISO 4217 e.g. 2 for GBP (100 pence in a pound) and 0 for JPY (no minor unit).
+//
+// Unknown currencies have an exponent of 2.
+func (c Currency) Exponent() int {
+	switch c {
+	case CurrencyISK, CurrencyJPY:
+		return 0
+	default:
+		return 2
+	}
+}
+
+// minorUnits is the number of minor units in a major unit of the currency e.g. 100 for GBP.
+func (c Currency) minorUnits() int {
+	units := 1
+	for range c.Exponent() {
+		units *= 10
+	}
+	return units
+}
+
 func (c *Currency) UnmarshalJSON(data []byte) error {
 	var currencyString string
 	err := json.Unmarshal(data, &currencyString)
@@ -114,3 +331,14 @@ func (c *Currency) UnmarshalJSON(data []byte) error {
 func (c Currency) MarshalJSON() ([]byte, error) {
 	return json.Marshal(c.Value)
 }
+
+func (c *Currency) UnmarshalText(data []byte) error {
+	currencyString := string(data)
+
+	currency := Currencies.Parse(currencyString)
+	if currency == nil {
+		return fmt.Errorf("currency '%s' is not valid", currencyString)
+	}
+	*c = *currency
+	return nil
+}
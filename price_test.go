@@ -3,6 +3,7 @@ package twigots
 import (
 	"encoding/json"
 	"testing"
+	"testing/quick"
 
 	"github.com/stretchr/testify/require"
 )
@@ -12,3 +13,147 @@ func TestCurrencyMarshalJSON(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, `"GBP"`, string(data))
 }
+
+func TestPriceString(t *testing.T) {
+	require.Equal(t, "£30.62", Price{Currency: CurrencyGBP, Amount: 3062}.String())
+	require.Equal(t, "£0.05", Price{Currency: CurrencyGBP, Amount: 5}.String())
+	require.Equal(t, "-£1.50", Price{Currency: CurrencyGBP, Amount: -150}.String())
+	require.Equal(t, "€12.00", Price{Currency: CurrencyEUR, Amount: 1200}.String())
+	require.Equal(t, "¥1200", Price{Currency: CurrencyJPY, Amount: 1200}.String())
+	require.Equal(t, "99.95 CHF", Price{Currency: CurrencyCHF, Amount: 9995}.String())
+}
+
+func TestPriceNumber(t *testing.T) {
+	require.InDelta(t, 30.62, Price{Currency: CurrencyGBP, Amount: 3062}.Number(), 1e-9)
+	require.InDelta(t, 1200, Price{Currency: CurrencyJPY, Amount: 1200}.Number(), 1e-9)
+}
+
+func TestPriceArithmetic(t *testing.T) {
+	price := Price{Currency: CurrencyGBP, Amount: 1000}
+
+	sum, err := price.Add(Price{Currency: CurrencyGBP, Amount: 250})
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 1250}, sum)
+
+	difference, err := price.Subtract(Price{Currency: CurrencyGBP, Amount: 250})
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyGBP, Amount: 750}, difference)
+
+	// Zero price with no currency can be used with any currency
+	sum, err = Price{}.Add(price)
+	require.NoError(t, err)
+	require.Equal(t, price, sum)
+
+	// Mismatched currencies return an error
+	_, err = price.Add(Price{Currency: CurrencyEUR, Amount: 250})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	_, err = price.Subtract(Price{Currency: CurrencyEUR, Amount: 250})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+}
+
+func TestPriceDivide(t *testing.T) {
+	testCases := []struct {
+		amount   int
+		num      int
+		expected int
+	}{
+		{1000, 4, 250},
+		{1000, 3, 333},
+		{1001, 2, 501}, // Halves round away from zero
+		{1003, 2, 502},
+		{-1001, 2, -501},
+		{1000, 0, 0},
+	}
+
+	for _, testCase := range testCases {
+		price := Price{Currency: CurrencyGBP, Amount: testCase.amount}.Divide(testCase.num)
+		require.Equal(
+			t, Price{Currency: CurrencyGBP, Amount: testCase.expected}, price,
+			"%d divided by %d", testCase.amount, testCase.num,
+		)
+	}
+}
+
+func TestPriceCompare(t *testing.T) {
+	cheap := Price{Currency: CurrencyGBP, Amount: 1000}
+	expensive := Price{Currency: CurrencyGBP, Amount: 2000}
+
+	cmp, err := cheap.Cmp(expensive)
+	require.NoError(t, err)
+	require.Equal(t, -1, cmp)
+
+	cmp, err = expensive.Cmp(cheap)
+	require.NoError(t, err)
+	require.Equal(t, 1, cmp)
+
+	cmp, err = cheap.Cmp(cheap)
+	require.NoError(t, err)
+	require.Equal(t, 0, cmp)
+
+	less, err := cheap.Less(expensive)
+	require.NoError(t, err)
+	require.True(t, less)
+
+	_, err = cheap.Less(Price{Currency: CurrencyEUR, Amount: 2000})
+	require.ErrorIs(t, err, ErrCurrencyMismatch)
+
+	require.True(t, cheap.Equal(Price{Currency: CurrencyGBP, Amount: 1000}))
+	require.False(t, cheap.Equal(Price{Currency: CurrencyEUR, Amount: 1000}))
+}
+
+func TestCurrencyUnmarshalJSON(t *testing.T) {
+	var price Price
+	err := json.Unmarshal([]byte(`{"currencyCode": "JPY", "amountInCents": 5000}`), &price)
+	require.NoError(t, err)
+	require.Equal(t, Price{Currency: CurrencyJPY, Amount: 5000}, price)
+	require.Equal(t, 0, price.Currency.Exponent())
+
+	err = json.Unmarshal([]byte(`{"currencyCode": "XYZ", "amountInCents": 5000}`), &price)
+	require.Error(t, err)
+}
+
+func TestPriceSplit(t *testing.T) {
+	parts := Price{Currency: CurrencyGBP, Amount: 1000}.Split(3)
+	require.Equal(t, []Price{
+		{Currency: CurrencyGBP, Amount: 334},
+		{Currency: CurrencyGBP, Amount: 333},
+		{Currency: CurrencyGBP, Amount: 333},
+	}, parts)
+
+	parts = Price{Currency: CurrencyGBP, Amount: -1000}.Split(3)
+	require.Equal(t, []Price{
+		{Currency: CurrencyGBP, Amount: -334},
+		{Currency: CurrencyGBP, Amount: -333},
+		{Currency: CurrencyGBP, Amount: -333},
+	}, parts)
+
+	require.Nil(t, Price{Currency: CurrencyGBP, Amount: 1000}.Split(0))
+}
+
+func TestPriceSplitProperties(t *testing.T) {
+	splits := func(amount int32, num uint8) bool {
+		price := Price{Currency: CurrencyEUR, Amount: int(amount)}
+		parts := price.Split(int(num))
+		if num == 0 {
+			return parts == nil
+		}
+		if len(parts) != int(num) {
+			return false
+		}
+
+		// Parts should add up to the price, and differ by at most one minor unit
+		sum := 0
+		minAmount, maxAmount := parts[0].Amount, parts[0].Amount
+		for _, part := range parts {
+			if part.Currency != price.Currency {
+				return false
+			}
+			sum += part.Amount
+			minAmount = min(minAmount, part.Amount)
+			maxAmount = max(maxAmount, part.Amount)
+		}
+		return sum == price.Amount && maxAmount-minAmount <= 1
+	}
+	require.NoError(t, quick.Check(splits, nil))
+}
@@ -0,0 +1,102 @@
+package rank
+
+import (
+	"sort"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// Scorer scores ticket listings, so they can be ranked.
+type Scorer struct {
+	// Name of the scorer, used in score breakdowns.
+	Name string
+
+	// Weight of the scorer relative to other scorers.
+	// Scorers with a weight <=0 will be ignored.
+	Weight float64
+
+	// Score returns the score of a ticket listing.
+	// Score should be a float between 0 and 1 (with 1 being the best).
+	Score func(twigots.TicketListing) float64
+}
+
+// Component is the score of a ticket listing from a single scorer.
+type Component struct {
+	Name   string
+	Weight float64
+	Score  float64
+}
+
+// RankedTicketListing is a ticket listing with its score, and a breakdown of the score from each scorer.
+type RankedTicketListing struct {
+	Listing twigots.TicketListing
+
+	// Score is the weighted average score from all scorers.
+	// Score is a float between 0 and 1 (with 1 being the best).
+	Score float64
+
+	// Breakdown is the score from each scorer, in the order the scorers were provided.
+	Breakdown []Component
+}
+
+// Score scores a ticket listing using the provided scorers.
+//
+// The score is the weighted average score from all scorers.
+// If no scorers are provided (or all have a weight <=0), the score will be 0.
+func Score(listing twigots.TicketListing, scorers ...Scorer) RankedTicketListing {
+	ranked := RankedTicketListing{
+		Listing:   listing,
+		Breakdown: make([]Component, 0, len(scorers)),
+	}
+
+	var totalScore, totalWeight float64
+	for _, scorer := range scorers {
+		if scorer.Weight <= 0 || scorer.Score == nil {
+			continue
+		}
+
+		score := clamp(scorer.Score(listing))
+		ranked.Breakdown = append(ranked.Breakdown, Component{
+			Name:   scorer.Name,
+			Weight: scorer.Weight,
+			Score:  score,
+		})
+
+		totalScore += scorer.Weight * score
+		totalWeight += scorer.Weight
+	}
+
+	if totalWeight > 0 {
+		ranked.Score = totalScore / totalWeight
+	}
+
+	return ranked
+}
+
+// Sort scores and sorts ticket listings using the provided scorers, with the best listing first.
+// Listings with equal scores keep their original order.
+//
+// See Score for how listings are scored.
+func Sort(listings []twigots.TicketListing, scorers ...Scorer) []RankedTicketListing {
+	rankedListings := make([]RankedTicketListing, 0, len(listings))
+	for idx := 0; idx < len(listings); idx++ {
+		rankedListings = append(rankedListings, Score(listings[idx], scorers...))
+	}
+
+	sort.SliceStable(rankedListings, func(i, j int) bool {
+		return rankedListings[i].Score > rankedListings[j].Score
+	})
+
+	return rankedListings
+}
+
+// clamp clamps a score between 0 and 1.
+func clamp(score float64) float64 {
+	if score < 0 {
+		return 0
+	}
+	if score > 1 {
+		return 1
+	}
+	return score
+}
@@ -0,0 +1,71 @@
+package rank
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScore(t *testing.T) {
+	// 25% discount
+	listing := twigots.TicketListing{
+		Event:              twigots.Event{Name: "Coldplay"},
+		NumTickets:         2,
+		TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 150 * 100},
+		OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+	}
+
+	ranked := Score(listing, Discount(1), EventName(3, "Coldplay"))
+	require.InDelta(t, (0.25+3)/4, ranked.Score, 0.001)
+	require.Len(t, ranked.Breakdown, 2)
+	require.Equal(t, "Discount", ranked.Breakdown[0].Name)
+	require.InDelta(t, 0.25, ranked.Breakdown[0].Score, 0.001)
+	require.Equal(t, "EventName", ranked.Breakdown[1].Name)
+	require.InDelta(t, 1, ranked.Breakdown[1].Score, 0.001)
+
+	// Scorers with no weight should be ignored
+	ranked = Score(listing, Discount(1), EventName(0, "Coldplay"))
+	require.InDelta(t, 0.25, ranked.Score, 0.001)
+	require.Len(t, ranked.Breakdown, 1)
+
+	// No scorers should score 0
+	ranked = Score(listing)
+	require.Zero(t, ranked.Score)
+}
+
+func TestSort(t *testing.T) {
+	listings := []twigots.TicketListing{
+		{ // 5% discount
+			Id:                 "1",
+			NumTickets:         2,
+			TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 190 * 100},
+			OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+		},
+		{ // 50% discount
+			Id:                 "2",
+			NumTickets:         2,
+			TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 100 * 100},
+			OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+		},
+		{ // 25% discount
+			Id:                 "3",
+			NumTickets:         2,
+			TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 150 * 100},
+			OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+		},
+		{ // 50% discount
+			Id:                 "4",
+			NumTickets:         2,
+			TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 100 * 100},
+			OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+		},
+	}
+
+	ranked := Sort(listings, Discount(1))
+	require.Len(t, ranked, 4)
+	require.Equal(t, "2", ranked[0].Listing.Id)
+	require.Equal(t, "4", ranked[1].Listing.Id) // Equal scores should keep original order
+	require.Equal(t, "3", ranked[2].Listing.Id)
+	require.Equal(t, "1", ranked[3].Listing.Id)
+}
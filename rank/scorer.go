@@ -0,0 +1,183 @@
+package rank
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/filter"
+)
+
+// timeNow is the current time. This is a variable so it can be changed in tests.
+var timeNow = time.Now
+
+// Discount creates a scorer that scores ticket listings by their discount on the original price.
+//
+// Score is the discount, between 0 and 1. Listings with no discount score 0.
+func Discount(weight float64) Scorer {
+	return Scorer{
+		Name:   "Discount",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			return listing.Discount()
+		},
+	}
+}
+
+// FaceValue creates a scorer that scores ticket listings by their total price incl fee relative to their
+// original price (face value).
+//
+// Listings that are free score 1, listings at face value score 0.5 and listings at
+// double face value (or more) score 0. Listings with an unknown face value score 0.
+func FaceValue(weight float64) Scorer {
+	return Scorer{
+		Name:   "FaceValue",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			price := listing.TotalPriceInclFee()
+			faceValue := listing.OriginalTotalPrice
+			if price.Currency != faceValue.Currency || faceValue.Amount <= 0 {
+				return 0
+			}
+
+			priceRatio := float64(price.Amount) / float64(faceValue.Amount)
+			return 1 - priceRatio/2
+		},
+	}
+}
+
+// Budget creates a scorer that scores ticket listings by their ticket price incl fee relative to a maximum
+// ticket price (budget).
+//
+// Listings that are free score 1, listings at half the budget score 0.5 and listings at (or over) the budget
+// score 0. Listings priced in a different currency to the budget are converted to the currency of the budget
+// using converter. If converter is nil, or the price cannot be converted, the listing scores 0.
+func Budget(weight float64, maxPrice twigots.Price, converter twigots.Converter) Scorer {
+	return Scorer{
+		Name:   "Budget",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			if maxPrice.Amount <= 0 {
+				return 0
+			}
+
+			price := listing.TicketPriceInclFee()
+			if price.Currency != maxPrice.Currency {
+				if converter == nil {
+					return 0
+				}
+
+				var err error
+				price, err = converter.Convert(price, maxPrice.Currency)
+				if err != nil {
+					return 0
+				}
+			}
+
+			priceRatio := float64(price.Amount) / float64(maxPrice.Amount)
+			return 1 - priceRatio
+		},
+	}
+}
+
+// SectionProximity creates a scorer that scores ticket listings by how close their section is to the
+// desired section.
+//
+// Listings in the desired section score 1. Listings in another section that can be ordered relative to the
+// desired section (e.g. "101" and "103", or "A" and "C") score 1/(1+distance). All other listings score 0.
+func SectionProximity(weight float64, section string) Scorer {
+	return Scorer{
+		Name:   "SectionProximity",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			return seatLabelProximity(section, listing.Section)
+		},
+	}
+}
+
+// RowProximity creates a scorer that scores ticket listings by how close their row is to the desired row.
+//
+// Listings in the desired row score 1. Listings in another row that can be ordered relative to the
+// desired row (e.g. "A" and "C", or "1" and "3") score 1/(1+distance). All other listings score 0.
+func RowProximity(weight float64, row string) Scorer {
+	return Scorer{
+		Name:   "RowProximity",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			return seatLabelProximity(row, listing.Row)
+		},
+	}
+}
+
+// NumTickets creates a scorer that scores ticket listings by how well their number of tickets matches the
+// desired number of tickets.
+//
+// Listings that the desired number of tickets can be bought from score 1.
+// All other listings score 1/(1+difference).
+func NumTickets(weight float64, numTickets int) Scorer {
+	return Scorer{
+		Name:   "NumTickets",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			if listing.CanBuy(numTickets) {
+				return 1
+			}
+
+			difference := math.Abs(float64(listing.NumTickets - numTickets))
+			return 1 / (1 + difference)
+		},
+	}
+}
+
+// Freshness creates a scorer that scores ticket listings by how recently they were created.
+//
+// Listings created now score 1, with the score halving every halfLife.
+// If halfLife is <=0, a half life of 1 hour is used.
+func Freshness(weight float64, halfLife time.Duration) Scorer {
+	if halfLife <= 0 {
+		halfLife = time.Hour
+	}
+
+	return Scorer{
+		Name:   "Freshness",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			age := timeNow().Sub(listing.CreatedAt.Time)
+			if age <= 0 {
+				return 1
+			}
+			return math.Pow(0.5, float64(age)/float64(halfLife))
+		},
+	}
+}
+
+// EventName creates a scorer that scores ticket listings by how similar their event name is to the
+// desired event name.
+//
+// Score is the event name similarity. See filter.NameSimilarity.
+func EventName(weight float64, eventName string) Scorer {
+	return Scorer{
+		Name:   "EventName",
+		Weight: weight,
+		Score: func(listing twigots.TicketListing) float64 {
+			return filter.NameSimilarity(eventName, listing.Event.Name)
+		},
+	}
+}
+
+// seatLabelProximity calculates the proximity score of a seat label (e.g. section or row) to a desired label.
+func seatLabelProximity(desiredLabel, label string) float64 {
+	if label == "" {
+		return 0
+	}
+	if strings.EqualFold(strings.TrimSpace(desiredLabel), strings.TrimSpace(label)) {
+		return 1
+	}
+
+	distance, ok := filter.SeatLabelDistance(desiredLabel, label)
+	if !ok {
+		return 0
+	}
+	return 1 / (1 + float64(distance))
+}
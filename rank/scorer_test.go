@@ -0,0 +1,94 @@
+package rank
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFaceValueScorer(t *testing.T) {
+	scorer := FaceValue(1)
+	listing := twigots.TicketListing{
+		NumTickets:         2,
+		TotalPriceExclFee:  twigots.Price{Currency: twigots.CurrencyGBP, Amount: 100 * 100},
+		OriginalTotalPrice: twigots.Price{Currency: twigots.CurrencyGBP, Amount: 200 * 100},
+	}
+	require.InDelta(t, 0.75, scorer.Score(listing), 0.001)
+
+	listing.TotalPriceExclFee.Amount = 200 * 100
+	require.InDelta(t, 0.5, scorer.Score(listing), 0.001)
+
+	listing.TotalPriceExclFee.Amount = 400 * 100
+	require.InDelta(t, 0, scorer.Score(listing), 0.001)
+
+	// Unknown face value
+	listing.OriginalTotalPrice = twigots.Price{}
+	require.Zero(t, scorer.Score(listing))
+}
+
+func TestSeatProximityScorers(t *testing.T) {
+	scorer := RowProximity(1, "C")
+	require.InDelta(t, 1, scorer.Score(twigots.TicketListing{Row: "c"}), 0.001)
+	require.InDelta(t, 1.0/3, scorer.Score(twigots.TicketListing{Row: "A"}), 0.001)
+	require.Zero(t, scorer.Score(twigots.TicketListing{Row: "1"}))
+	require.Zero(t, scorer.Score(twigots.TicketListing{}))
+
+	scorer = SectionProximity(1, "Upper Tier")
+	require.InDelta(t, 1, scorer.Score(twigots.TicketListing{Section: "upper tier"}), 0.001)
+	require.Zero(t, scorer.Score(twigots.TicketListing{Section: "101"}))
+}
+
+func TestNumTicketsScorer(t *testing.T) {
+	scorer := NumTickets(1, 2)
+	require.InDelta(t, 1, scorer.Score(twigots.TicketListing{NumTickets: 2}), 0.001)
+	require.InDelta(t, 1, scorer.Score(twigots.TicketListing{NumTickets: 4, SplitRule: twigots.SplitRuleAny}), 0.001)
+	require.InDelta(t, 1.0/3, scorer.Score(twigots.TicketListing{NumTickets: 4}), 0.001)
+}
+
+func TestFreshnessScorer(t *testing.T) {
+	currentTime := time.Now()
+	timeNow = func() time.Time { return currentTime }
+	defer func() { timeNow = time.Now }()
+
+	scorer := Freshness(1, time.Hour)
+	newListing := twigots.TicketListing{CreatedAt: twigots.UnixTime{Time: currentTime}}
+	hourOldListing := twigots.TicketListing{CreatedAt: twigots.UnixTime{Time: currentTime.Add(-time.Hour)}}
+	twoHourOldListing := twigots.TicketListing{CreatedAt: twigots.UnixTime{Time: currentTime.Add(-2 * time.Hour)}}
+
+	require.InDelta(t, 1, scorer.Score(newListing), 0.001)
+	require.InDelta(t, 0.5, scorer.Score(hourOldListing), 0.001)
+	require.InDelta(t, 0.25, scorer.Score(twoHourOldListing), 0.001)
+}
+
+func TestBudgetScorer(t *testing.T) {
+	budget := twigots.Price{Currency: twigots.CurrencyGBP, Amount: 80 * 100}
+
+	scorer := Budget(1, budget, nil)
+	listing := twigots.TicketListing{
+		NumTickets:        2,
+		TotalPriceExclFee: budget,
+	}
+	require.InDelta(t, 0.5, scorer.Score(listing), 0.001)
+
+	listing.NumTickets = 1
+	require.InDelta(t, 0, scorer.Score(listing), 0.001)
+
+	// Listings in a different currency cannot be scored without a converter
+	listing = twigots.TicketListing{
+		NumTickets:        2,
+		TotalPriceExclFee: twigots.Price{Currency: twigots.CurrencyEUR, Amount: 100 * 100},
+		TwicketsFee:       twigots.Price{Currency: twigots.CurrencyEUR},
+	}
+	require.Zero(t, scorer.Score(listing))
+
+	converter, err := twigots.NewStaticConverter(twigots.CurrencyGBP, map[twigots.Currency]float64{
+		twigots.CurrencyEUR: 1.25,
+	})
+	require.NoError(t, err)
+
+	// €50 per ticket is £40
+	scorer = Budget(1, budget, converter)
+	require.InDelta(t, 0.5, scorer.Score(listing), 0.001)
+}
@@ -0,0 +1,72 @@
+package twigots
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/orsinium-labs/enum"
+)
+
+var (
+	splitRule = enum.NewBuilder[string, SplitRule]()
+
+	// SplitRuleAny allows any number of tickets in a listing to be bought.
+	SplitRuleAny = splitRule.Add(SplitRule{"ANY"})
+	// SplitRuleNone only allows all tickets in a listing to be bought together.
+	SplitRuleNone = splitRule.Add(SplitRule{"NONE"})
+	// SplitRuleAvoidSingle allows any number of tickets in a listing to be bought,
+	// as long as a single ticket is not left over.
+	SplitRuleAvoidSingle = splitRule.Add(SplitRule{"AVOID_SINGLE"})
+	// SplitRulePairs only allows tickets in a listing to be bought in pairs,
+	// or all tickets to be bought together.
+	SplitRulePairs = splitRule.Add(SplitRule{"PAIRS"})
+
+	SplitRules = splitRule.Enum()
+)
+
+// SplitRule is the rule that determines how the tickets in a listing can be split when buying.
+type SplitRule enum.Member[string]
+
+// CanBuy checks whether the number of tickets specified can be bought from
+// a listing with the total number of tickets, according to the split rule.
+//
+// Unknown or unset split rules are treated as SplitRuleNone, as the whole listing can always be bought.
+func (s SplitRule) CanBuy(numTickets, totalNumTickets int) bool {
+	if numTickets <= 0 || numTickets > totalNumTickets {
+		return false
+	}
+	if numTickets == totalNumTickets {
+		return true
+	}
+
+	switch s {
+	case SplitRuleAny:
+		return true
+	case SplitRuleAvoidSingle:
+		return totalNumTickets-numTickets != 1
+	case SplitRulePairs:
+		return numTickets%2 == 0
+	default:
+		return false
+	}
+}
+
+func (s *SplitRule) UnmarshalJSON(data []byte) error {
+	var splitRuleString string
+	err := json.Unmarshal(data, &splitRuleString)
+	if err != nil {
+		return err
+	}
+
+	splitRule := SplitRules.Parse(splitRuleString)
+	if splitRule == nil {
+		return fmt.Errorf("split rule '%s' is not valid", splitRuleString)
+	}
+
+	*s = *splitRule
+	return nil
+}
+
+func (s SplitRule) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.Value)
+}
@@ -0,0 +1,27 @@
+package twigots
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitRuleCanBuy(t *testing.T) {
+	require.True(t, SplitRuleNone.CanBuy(4, 4))
+	require.False(t, SplitRuleNone.CanBuy(2, 4))
+
+	require.True(t, SplitRuleAny.CanBuy(1, 4))
+	require.False(t, SplitRuleAny.CanBuy(5, 4))
+	require.False(t, SplitRuleAny.CanBuy(0, 4))
+
+	require.True(t, SplitRuleAvoidSingle.CanBuy(2, 4))
+	require.False(t, SplitRuleAvoidSingle.CanBuy(3, 4))
+
+	require.True(t, SplitRulePairs.CanBuy(2, 4))
+	require.False(t, SplitRulePairs.CanBuy(1, 4))
+	require.True(t, SplitRulePairs.CanBuy(3, 3))
+
+	// Unset split rule should be treated as none
+	require.False(t, SplitRule{}.CanBuy(2, 4))
+	require.True(t, SplitRule{}.CanBuy(4, 4))
+}
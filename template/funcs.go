@@ -0,0 +1,156 @@
+package template
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"golang.org/x/text/language"
+)
+
+const (
+	// DefaultEventStartLayout is the default layout used to format event start times.
+	DefaultEventStartLayout = "Mon 2 Jan 2006 15:04"
+	// DefaultEventDateLayout is the default layout used to format event dates, if the start time is unknown.
+	DefaultEventDateLayout = "Mon 2 Jan 2006"
+)
+
+// timeNow is the current time. This is a variable so it can be changed in tests.
+var timeNow = time.Now
+
+// Funcs are the helper functions available in templates:
+//
+//   - price: formats a price e.g. {{price .TicketPrice}} gives £30.62.
+//     An optional locale formats the price using the conventions of the locale
+//     e.g. {{price .TicketPrice "de"}} gives 30,62 £. See twigots.Price.Format.
+//   - discount: formats a discount as a percentage e.g. {{discount .Discount}} gives 10.00%.
+//     Negative discounts (prices above the original price) give "none".
+//   - eventStart: formats the start time of an event in the time zone of its venue
+//     e.g. {{eventStart .Event}} gives Sat 12 Jul 2025 19:30. If the start time is unknown, the date of the event
+//     is formatted instead, or "unknown date" if that is also unknown. An optional layout sets the time format
+//     e.g. {{eventStart .Event "15:04"}}.
+//   - regionName: gets the name of a region from the region or its code e.g. {{regionName .Event.Venue.Region}}
+//     gives North West. See twigots.ParseRegion.
+//   - listingURL: gets the url of a listing e.g. {{listingURL .}}.
+//   - relativeTime: formats a time relative to now e.g. {{relativeTime .CreatedAt}} gives 5 minutes ago.
+//
+// Functions accept views (e.g. Listing and Event) or the twigots types they are views of.
+// Funcs returns a new map on every call, so it can be modified.
+func Funcs() map[string]any {
+	return map[string]any{
+		"price":        formatPrice,
+		"discount":     formatDiscount,
+		"eventStart":   formatEventStart,
+		"regionName":   regionName,
+		"listingURL":   listingURL,
+		"relativeTime": relativeTime,
+	}
+}
+
+func formatPrice(price twigots.Price, locale ...string) (string, error) {
+	if len(locale) == 0 {
+		return price.String(), nil
+	}
+	if len(locale) > 1 {
+		return "", fmt.Errorf("expected at most 1 locale, got %d", len(locale))
+	}
+
+	tag, err := language.Parse(locale[0])
+	if err != nil {
+		return "", fmt.Errorf("locale '%s' is not valid: %w", locale[0], err)
+	}
+	return price.Format(tag), nil
+}
+
+func formatDiscount(discount float64) string {
+	if discount < 0 {
+		return "none"
+	}
+	return strconv.FormatFloat(discount*100, 'f', 2, 64) + "%"
+}
+
+func formatEventStart(event any, layout ...string) (string, error) {
+	var eventView Event
+	switch event := event.(type) {
+	case Event:
+		eventView = event
+	case twigots.Event:
+		eventView = NewEvent(event)
+	default:
+		return "", fmt.Errorf("expected an event, got %T", event)
+	}
+	if len(layout) > 1 {
+		return "", fmt.Errorf("expected at most 1 layout, got %d", len(layout))
+	}
+
+	switch {
+	case !eventView.StartsAt.IsZero():
+		if len(layout) == 1 {
+			return eventView.StartsAt.Format(layout[0]), nil
+		}
+		return eventView.StartsAt.Format(DefaultEventStartLayout), nil
+	case !eventView.Date.IsZero():
+		if len(layout) == 1 {
+			return eventView.Date.Format(layout[0]), nil
+		}
+		return eventView.Date.Format(DefaultEventDateLayout), nil
+	default:
+		return "unknown date", nil
+	}
+}
+
+func regionName(region any) (string, error) {
+	switch region := region.(type) {
+	case twigots.Region:
+		return region.String(), nil
+	case string:
+		parsedRegion, err := twigots.ParseRegion(region)
+		if err == nil {
+			return parsedRegion.String(), nil
+		}
+		// Use the code as is, rather than failing to render
+		return region, nil
+	default:
+		return "", fmt.Errorf("expected a region, got %T", region)
+	}
+}
+
+func listingURL(listing any) (string, error) {
+	switch listing := listing.(type) {
+	case Listing:
+		return listing.URL, nil
+	case twigots.TicketListing:
+		return listing.URL(), nil
+	default:
+		return "", fmt.Errorf("expected a listing, got %T", listing)
+	}
+}
+
+func relativeTime(t time.Time) string {
+	duration := timeNow().Sub(t)
+	isFuture := duration < 0
+	duration = duration.Abs()
+
+	var amount int
+	var unit string
+	switch {
+	case duration < time.Minute:
+		return "just now"
+	case duration < time.Hour:
+		amount, unit = int(duration/time.Minute), "minute"
+	case duration < 24*time.Hour:
+		amount, unit = int(duration/time.Hour), "hour"
+	default:
+		amount, unit = int(math.Round(duration.Hours()/24)), "day"
+	}
+	if amount != 1 {
+		unit += "s"
+	}
+
+	if isFuture {
+		return fmt.Sprintf("in %d %s", amount, unit)
+	}
+	return fmt.Sprintf("%d %s ago", amount, unit)
+}
@@ -0,0 +1,112 @@
+package template
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatPrice(t *testing.T) {
+	price := twigots.Price{Currency: twigots.CurrencyGBP, Amount: 123456}
+
+	formatted, err := formatPrice(price)
+	require.NoError(t, err)
+	require.Equal(t, "£1234.56", formatted)
+
+	formatted, err = formatPrice(price, "en")
+	require.NoError(t, err)
+	require.Equal(t, "£1,234.56", formatted)
+
+	_, err = formatPrice(price, "not a locale!")
+	require.Error(t, err)
+}
+
+func TestFormatDiscount(t *testing.T) {
+	require.Equal(t, "12.50%", formatDiscount(0.125))
+	require.Equal(t, "0.00%", formatDiscount(0))
+	require.Equal(t, "none", formatDiscount(-0.1))
+}
+
+func TestFormatEventStart(t *testing.T) {
+	listing := SampleListings()[0]
+
+	// Start time should be in the time zone of the venue (BST)
+	formatted, err := formatEventStart(listing.Event)
+	require.NoError(t, err)
+	require.Equal(t, "Sat 12 Jul 2025 19:30", formatted)
+
+	formatted, err = formatEventStart(NewEvent(listing.Event), time.Kitchen)
+	require.NoError(t, err)
+	require.Equal(t, "7:30PM", formatted)
+
+	// Date should be used if the start time is unknown
+	listing.Event.Time = twigots.Time{}
+	formatted, err = formatEventStart(listing.Event)
+	require.NoError(t, err)
+	require.Equal(t, "Sat 12 Jul 2025", formatted)
+
+	formatted, err = formatEventStart(twigots.Event{})
+	require.NoError(t, err)
+	require.Equal(t, "unknown date", formatted)
+
+	_, err = formatEventStart("not an event")
+	require.Error(t, err)
+}
+
+func TestRegionName(t *testing.T) {
+	name, err := regionName(twigots.RegionNorthWest)
+	require.NoError(t, err)
+	require.Equal(t, "North West", name)
+
+	name, err = regionName("GBLO")
+	require.NoError(t, err)
+	require.Equal(t, "London", name)
+
+	name, err = regionName("XXXX")
+	require.NoError(t, err)
+	require.Equal(t, "XXXX", name)
+
+	_, err = regionName(1)
+	require.Error(t, err)
+}
+
+func TestListingURL(t *testing.T) {
+	listing := SampleListings()[0]
+
+	url, err := listingURL(listing)
+	require.NoError(t, err)
+	require.Equal(t, "https://www.twickets.live/app/block/sample-full,2", url)
+
+	url, err = listingURL(NewListing(listing))
+	require.NoError(t, err)
+	require.Equal(t, "https://www.twickets.live/app/block/sample-full,2", url)
+
+	_, err = listingURL(listing.Event)
+	require.Error(t, err)
+}
+
+func TestRelativeTime(t *testing.T) {
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	timeNow = func() time.Time { return now }
+	defer func() { timeNow = time.Now }()
+
+	tests := []struct {
+		time     time.Time
+		expected string
+	}{
+		{time: now.Add(-30 * time.Second), expected: "just now"},
+		{time: now.Add(-time.Minute), expected: "1 minute ago"},
+		{time: now.Add(-5 * time.Minute), expected: "5 minutes ago"},
+		{time: now.Add(-3 * time.Hour), expected: "3 hours ago"},
+		{time: now.Add(-24 * time.Hour), expected: "1 day ago"},
+		{time: now.Add(40 * time.Minute), expected: "in 40 minutes"},
+		{time: now.Add(10 * 24 * time.Hour), expected: "in 10 days"},
+	}
+	for _, test := range tests {
+		t.Run(test.expected, func(t *testing.T) {
+			require.Equal(t, test.expected, relativeTime(test.time))
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package template
+
+import (
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// SampleListings are sample ticket listings that templates are validated against when they are parsed.
+//
+// The samples cover a listing with all details set, and a listing with only the required details set
+// (e.g. with no original price, start time or region), so templates must handle missing details.
+func SampleListings() []twigots.TicketListing {
+	createdAt := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	return []twigots.TicketListing{
+		{
+			Id:                       "sample-full",
+			CreatedAt:                twigots.UnixTime{Time: createdAt},
+			ExpiresAt:                twigots.UnixTime{Time: createdAt.Add(30 * 24 * time.Hour)},
+			NumTickets:               2,
+			TotalPriceExclFee:        twigots.Price{Currency: twigots.CurrencyGBP, Amount: 16000},
+			TwicketsFee:              twigots.Price{Currency: twigots.CurrencyGBP, Amount: 2000},
+			OriginalTotalPrice:       twigots.Price{Currency: twigots.CurrencyGBP, Amount: 20000},
+			SellerWillConsiderOffers: true,
+			TicketType:               "Seated",
+			SeatAssigned:             true,
+			Section:                  "Block 101",
+			Row:                      "K",
+			Event: twigots.Event{
+				Id:       "sample-event",
+				Name:     "Oasis Live '25",
+				Category: "Concert",
+				Date:     twigots.Date{Time: time.Date(2025, 7, 12, 0, 0, 0, 0, time.UTC)},
+				Time:     twigots.Time{Time: time.Date(0, 1, 1, 19, 30, 0, 0, time.UTC)},
+				Venue: twigots.Venue{
+					Id:       "sample-venue",
+					Name:     "Wembley Stadium",
+					Postcode: "HA9 0WS",
+					Location: twigots.Location{
+						Id:           "sample-location",
+						Name:         "London",
+						FullName:     "London",
+						Country:      twigots.CountryUnitedKingdom,
+						Region:       twigots.RegionLondon,
+						TimeZoneName: "Europe/London",
+					},
+				},
+				Lineup: []twigots.Lineup{
+					{Artist: twigots.Artist{Id: "sample-artist", Name: "Oasis"}, Billing: 1},
+				},
+			},
+			Tour: twigots.Tour{
+				Id:        "sample-tour",
+				Name:      "Oasis Live '25",
+				Countries: []twigots.Country{twigots.CountryUnitedKingdom, twigots.CountryIreland},
+			},
+		},
+		{
+			Id:                "sample-minimal",
+			NumTickets:        1,
+			TotalPriceExclFee: twigots.Price{Currency: twigots.CurrencyEUR, Amount: 5000},
+			TwicketsFee:       twigots.Price{Currency: twigots.CurrencyEUR, Amount: 500},
+			Event: twigots.Event{
+				Name: "Sample Event",
+				Venue: twigots.Venue{
+					Name: "Sample Venue",
+				},
+			},
+		},
+	}
+}
@@ -0,0 +1,233 @@
+// Package template renders ticket listings using user-defined Go templates, such as for notifications.
+//
+// Templates are executed with a stable view of a ticket listing (see Listing), and can use helper functions
+// to format prices, discounts and times (see Funcs). Both text and html templates are supported.
+// Templates are validated against sample listings when they are parsed, so errors are caught when templates
+// are loaded, rather than when a listing is rendered.
+package template
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	htmltemplate "html/template"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	texttemplate "text/template"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// Template is a text or html template of a ticket listing.
+// Templates are executed with the view of a listing. See Listing and Funcs.
+//
+// A Template is safe for concurrent use.
+type Template struct {
+	name     string
+	isHTML   bool
+	template interface {
+		Execute(w io.Writer, data any) error
+	}
+}
+
+// Parse parses a text template, and validates it against the sample listings. See SampleListings.
+func Parse(name, text string) (*Template, error) {
+	tmpl, err := texttemplate.New(name).Option("missingkey=error").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template '%s': %w", name, err)
+	}
+	return newTemplate(name, false, tmpl)
+}
+
+// ParseHTML parses an html template, and validates it against the sample listings. See SampleListings.
+// Values are escaped according to their context. See html/template.
+func ParseHTML(name, text string) (*Template, error) {
+	tmpl, err := htmltemplate.New(name).Option("missingkey=error").Funcs(Funcs()).Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse template '%s': %w", name, err)
+	}
+	return newTemplate(name, true, tmpl)
+}
+
+// ParseFile parses a template file, and validates it against the sample listings. See SampleListings.
+// Files with a .html or .htm extension are parsed as html templates, and all other files as text templates.
+// The template is named after the file name.
+func ParseFile(path string) (*Template, error) {
+	return parseFile(filepath.Base(path), path)
+}
+
+func parseFile(name, path string) (*Template, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template file: %w", err)
+	}
+
+	if isHTMLFile(path) {
+		return ParseHTML(name, string(content))
+	}
+	return Parse(name, string(content))
+}
+
+func newTemplate(name string, isHTML bool, tmpl interface{ Execute(io.Writer, any) error }) (*Template, error) {
+	template := &Template{
+		name:     name,
+		isHTML:   isHTML,
+		template: tmpl,
+	}
+
+	err := template.validate()
+	if err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+// validate validates the template by executing it with each sample listing.
+func (t *Template) validate() error {
+	for _, listing := range SampleListings() {
+		_, err := t.Execute(listing)
+		if err != nil {
+			return fmt.Errorf("template '%s' is not valid for sample listing '%s': %w", t.name, listing.Id, err)
+		}
+	}
+	return nil
+}
+
+// Name is the name of the template.
+func (t *Template) Name() string {
+	return t.name
+}
+
+// IsHTML returns whether the template is an html template.
+func (t *Template) IsHTML() bool {
+	return t.isHTML
+}
+
+// Execute executes the template with the view of a ticket listing.
+func (t *Template) Execute(listing twigots.TicketListing) (string, error) {
+	return t.ExecuteView(NewListing(listing))
+}
+
+// ExecuteView executes the template with the view of a ticket listing.
+func (t *Template) ExecuteView(listing Listing) (string, error) {
+	var buffer bytes.Buffer
+	err := t.template.Execute(&buffer, listing)
+	if err != nil {
+		return "", err
+	}
+	return buffer.String(), nil
+}
+
+// Config is the config of a set of templates, such as from a config file.
+type Config struct {
+	// Dir is the directory relative file paths are resolved from. Defaults to the working directory.
+	Dir string `json:"dir"`
+	// Files are the paths of template files, keyed by template name.
+	// See ParseFile for how files are parsed.
+	Files map[string]string `json:"files"`
+	// Text are text templates, keyed by template name.
+	Text map[string]string `json:"text"`
+	// HTML are html templates, keyed by template name.
+	HTML map[string]string `json:"html"`
+}
+
+// ParseConfig parses the config of a set of templates from JSON e.g.
+//
+//	{"dir": "templates", "files": {"email": "email.html"}, "text": {"title": "{{.Event.Name}}"}}
+func ParseConfig(r io.Reader) (Config, error) {
+	var config Config
+	err := json.NewDecoder(r).Decode(&config)
+	if err != nil {
+		return Config{}, fmt.Errorf("failed to decode template config: %w", err)
+	}
+	return config, nil
+}
+
+// Set is a set of named templates.
+type Set struct {
+	templates map[string]*Template
+}
+
+// Load loads the templates of a config, validating each against the sample listings.
+// An error is returned if any template fails to load, or if a template name is used more than once.
+func Load(config Config) (*Set, error) {
+	set := &Set{templates: make(map[string]*Template)}
+	add := func(name string, template *Template, err error) error {
+		if err != nil {
+			return err
+		}
+		if _, ok := set.templates[name]; ok {
+			return fmt.Errorf("template '%s' is not unique", name)
+		}
+		set.templates[name] = template
+		return nil
+	}
+
+	for _, name := range sortedKeys(config.Files) {
+		path := config.Files[name]
+		if !filepath.IsAbs(path) {
+			path = filepath.Join(config.Dir, path)
+		}
+
+		template, err := parseFile(name, path)
+		err = add(name, template, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedKeys(config.Text) {
+		template, err := Parse(name, config.Text[name])
+		err = add(name, template, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	for _, name := range sortedKeys(config.HTML) {
+		template, err := ParseHTML(name, config.HTML[name])
+		err = add(name, template, err)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return set, nil
+}
+
+// Lookup gets a template by name.
+func (s *Set) Lookup(name string) (*Template, bool) {
+	template, ok := s.templates[name]
+	return template, ok
+}
+
+// Names are the names of the templates in the set, in alphabetical order.
+func (s *Set) Names() []string {
+	return sortedKeys(s.templates)
+}
+
+// Execute executes a template by name with the view of a ticket listing.
+func (s *Set) Execute(name string, listing twigots.TicketListing) (string, error) {
+	template, ok := s.Lookup(name)
+	if !ok {
+		return "", fmt.Errorf("template '%s' does not exist", name)
+	}
+	return template.Execute(listing)
+}
+
+func isHTMLFile(path string) bool {
+	extension := strings.ToLower(filepath.Ext(path))
+	return extension == ".html" || extension == ".htm"
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
@@ -0,0 +1,128 @@
+package template_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots/template"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	tmpl, err := template.Parse(
+		"summary",
+		`{{.Event.Name}} at {{.Event.Venue.Name}} ({{regionName .Event.Venue.Region}}): `+
+			`{{.NumTickets}} x {{price .TicketPrice}} ({{discount .Discount}} off)`,
+	)
+	require.NoError(t, err)
+	require.Equal(t, "summary", tmpl.Name())
+	require.False(t, tmpl.IsHTML())
+
+	listing := template.SampleListings()[0]
+	summary, err := tmpl.Execute(listing)
+	require.NoError(t, err)
+	require.Equal(t, "Oasis Live '25 at Wembley Stadium (London): 2 x £90.00 (10.00% off)", summary)
+}
+
+func TestParseHTML(t *testing.T) {
+	tmpl, err := template.ParseHTML("summary", `<a href="{{listingURL .}}">{{.Event.Name}}</a>`)
+	require.NoError(t, err)
+	require.True(t, tmpl.IsHTML())
+
+	summary, err := tmpl.Execute(template.SampleListings()[0])
+	require.NoError(t, err)
+	require.Equal(t, `<a href="https://www.twickets.live/app/block/sample-full,2">Oasis Live &#39;25</a>`, summary)
+}
+
+func TestParseInvalid(t *testing.T) {
+	// Syntax error
+	_, err := template.Parse("invalid", "{{.Event.Name")
+	require.Error(t, err)
+
+	// Unknown field
+	_, err = template.Parse("invalid", "{{.Event.NotAField}}")
+	require.ErrorContains(t, err, "template 'invalid' is not valid for sample listing 'sample-full'")
+
+	// Unknown function
+	_, err = template.ParseHTML("invalid", "{{notAFunc .}}")
+	require.Error(t, err)
+
+	// Fails for the minimal sample listing, which has no event lineup
+	_, err = template.Parse("invalid", "{{index .Event.Artists 0}}")
+	require.ErrorContains(t, err, "template 'invalid' is not valid for sample listing 'sample-minimal'")
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	err := os.WriteFile(filepath.Join(dir, "body.txt"), []byte("{{.NumTickets}} ticket(s)"), 0o600)
+	require.NoError(t, err)
+	err = os.WriteFile(filepath.Join(dir, "email.html"), []byte("<p>{{.Event.Name}}</p>"), 0o600)
+	require.NoError(t, err)
+
+	config, err := template.ParseConfig(strings.NewReader(`{
+		"dir": "` + filepath.ToSlash(dir) + `",
+		"files": {"body": "body.txt", "email": "email.html"},
+		"text": {"title": "{{.Event.Name}}"}
+	}`))
+	require.NoError(t, err)
+
+	set, err := template.Load(config)
+	require.NoError(t, err)
+	require.Equal(t, []string{"body", "email", "title"}, set.Names())
+
+	listing := template.SampleListings()[0]
+	tests := map[string]string{
+		"body":  "2 ticket(s)",
+		"email": "<p>Oasis Live &#39;25</p>",
+		"title": "Oasis Live '25",
+	}
+	for name, expected := range tests {
+		output, err := set.Execute(name, listing)
+		require.NoError(t, err)
+		require.Equal(t, expected, output)
+	}
+
+	email, ok := set.Lookup("email")
+	require.True(t, ok)
+	require.True(t, email.IsHTML())
+
+	_, err = set.Execute("missing", listing)
+	require.Error(t, err)
+}
+
+func TestLoadInvalid(t *testing.T) {
+	_, err := template.Load(template.Config{Files: map[string]string{"missing": "missing.txt"}})
+	require.Error(t, err)
+
+	_, err = template.Load(template.Config{
+		Text: map[string]string{"title": "{{.Event.Name}}"},
+		HTML: map[string]string{"title": "{{.Event.Name}}"},
+	})
+	require.EqualError(t, err, "template 'title' is not unique")
+
+	_, err = template.Load(template.Config{HTML: map[string]string{"email": "{{.NotAField}}"}})
+	require.Error(t, err)
+}
+
+func TestNewListing(t *testing.T) {
+	listings := template.SampleListings()
+
+	view := template.NewListing(listings[0])
+	require.Equal(t, "sample-full", view.Id)
+	require.Equal(t, "£90.00", view.TicketPrice.String())
+	require.Equal(t, "£80.00", view.TicketPriceExclFee.String())
+	require.Equal(t, "£10.00", view.TicketFee.String())
+	require.Equal(t, "£100.00", view.OriginalTicketPrice.String())
+	require.Equal(t, "£180.00", view.TotalPrice.String())
+	require.InDelta(t, 0.1, view.Discount, 1e-9)
+	require.Equal(t, []string{"Oasis"}, view.Event.Artists)
+	require.Equal(t, "London", view.Event.Venue.Location)
+	require.Equal(t, "2025-07-12T19:30:00+01:00", view.Event.StartsAt.Format("2006-01-02T15:04:05Z07:00"))
+
+	view = template.NewListing(listings[1])
+	require.True(t, view.Event.StartsAt.IsZero())
+	require.True(t, view.Event.Date.IsZero())
+	require.Empty(t, view.Event.Artists)
+}
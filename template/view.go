@@ -0,0 +1,135 @@
+package template
+
+import (
+	"time"
+
+	"github.com/ahobsonsayers/twigots"
+)
+
+// Listing is the view of a ticket listing that templates are executed with.
+//
+// The view is stable. Fields will only ever be added, so templates will keep working as twigots changes.
+type Listing struct {
+	Id        string
+	URL       string
+	CreatedAt time.Time
+	ExpiresAt time.Time
+
+	NumTickets int
+	// TicketType is the type of the tickets e.g. Seated, Standing, Box etc.
+	TicketType string
+	// Section and Row of the tickets. Can be empty.
+	Section string
+	Row     string
+
+	// TicketPrice is the price of a single ticket, including fee.
+	TicketPrice         twigots.Price
+	TicketPriceExclFee  twigots.Price
+	TicketFee           twigots.Price
+	OriginalTicketPrice twigots.Price
+
+	// TotalPrice is the total price of all tickets, including fee.
+	TotalPrice         twigots.Price
+	TotalPriceExclFee  twigots.Price
+	TotalFee           twigots.Price
+	OriginalTotalPrice twigots.Price
+
+	// Discount is the discount on the original price of a single ticket, between 0 and 1.
+	// See twigots.TicketListing.Discount.
+	Discount                 float64
+	SellerWillConsiderOffers bool
+
+	Event Event
+	Tour  Tour
+}
+
+// Event is the view of an event.
+type Event struct {
+	Id       string
+	Name     string
+	Category string
+
+	// Date of the event. Zero if unknown.
+	Date time.Time
+	// StartsAt is the time the event starts, in the time zone of the venue. Zero if unknown.
+	// See twigots.Event.StartsAt.
+	StartsAt time.Time
+
+	Venue Venue
+	// Artists are the names of the artists in the lineup of the event.
+	Artists []string
+}
+
+// Venue is the view of an event venue.
+type Venue struct {
+	Id       string
+	Name     string
+	Postcode string
+	// Location is the name of the location of the venue e.g. London.
+	Location string
+	Region   twigots.Region
+	Country  twigots.Country
+}
+
+// Tour is the view of a tour.
+type Tour struct {
+	Id   string
+	Name string
+}
+
+// NewListing creates the view of a ticket listing.
+func NewListing(listing twigots.TicketListing) Listing {
+	return Listing{
+		Id:                       listing.Id,
+		URL:                      listing.URL(),
+		CreatedAt:                listing.CreatedAt.Time,
+		ExpiresAt:                listing.ExpiresAt.Time,
+		NumTickets:               listing.NumTickets,
+		TicketType:               listing.TicketType,
+		Section:                  listing.Section,
+		Row:                      listing.Row,
+		TicketPrice:              listing.TicketPriceInclFee(),
+		TicketPriceExclFee:       listing.TicketPriceExclFee(),
+		TicketFee:                listing.TwicketsFeePerTicket(),
+		OriginalTicketPrice:      listing.OriginalTicketPrice(),
+		TotalPrice:               listing.TotalPriceInclFee(),
+		TotalPriceExclFee:        listing.TotalPriceExclFee,
+		TotalFee:                 listing.TwicketsFee,
+		OriginalTotalPrice:       listing.OriginalTotalPrice,
+		Discount:                 listing.Discount(),
+		SellerWillConsiderOffers: listing.SellerWillConsiderOffers,
+		Event:                    NewEvent(listing.Event),
+		Tour: Tour{
+			Id:   listing.Tour.Id,
+			Name: listing.Tour.Name,
+		},
+	}
+}
+
+// NewEvent creates the view of an event.
+func NewEvent(event twigots.Event) Event {
+	artists := make([]string, 0, len(event.Lineup))
+	for _, lineup := range event.Lineup {
+		artists = append(artists, lineup.Artist.Name)
+	}
+
+	// Start time is zero if unknown
+	startsAt, _ := event.StartsAt()
+
+	return Event{
+		Id:       event.Id,
+		Name:     event.Name,
+		Category: event.Category,
+		Date:     event.Date.Time,
+		StartsAt: startsAt,
+		Venue: Venue{
+			Id:       event.Venue.Id,
+			Name:     event.Venue.Name,
+			Postcode: event.Venue.Postcode,
+			Location: event.Venue.Location.Name,
+			Region:   event.Venue.Location.Region,
+			Country:  event.Venue.Location.Country,
+		},
+		Artists: artists,
+	}
+}
@@ -43,7 +43,7 @@ type FeedUrlInput struct {
 	Country Country
 
 	// Optional fields
-	Regions    []Region  // Defaults to all country regions
+	Regions    []Region  // Defaults to all country regions. Must be regions of Country.
 	BeforeTime time.Time // Defaults to current time
 }
 
@@ -57,9 +57,9 @@ func (f FeedUrlInput) Validate() error {
 		return errors.New("country must be set")
 	}
 	if !Countries.Contains(f.Country) {
-		return fmt.Errorf("country '%s' is not valid", f.Country)
+		return fmt.Errorf("country '%s' is not valid", f.Country.Value)
 	}
-	return nil
+	return validateRegions(f.Country, f.Regions)
 }
 
 // FeedUrl gets the url of a ticket listings feed.
@@ -80,7 +80,10 @@ func FeedUrl(input FeedUrlInput) (string, error) {
 	// Set query params
 	queryParams := feedUrl.Query()
 
-	locationQuery := apiLocationQuery(input.Country, input.Regions...)
+	locationQuery, err := apiLocationQuery(input.Country, input.Regions...)
+	if err != nil {
+		return "", fmt.Errorf("invalid input parameters: %w", err)
+	}
 	if locationQuery != "" {
 		queryParams.Set("q", locationQuery)
 	}
@@ -102,10 +105,15 @@ func FeedUrl(input FeedUrlInput) (string, error) {
 	return feedUrl.String(), nil
 }
 
-// apiLocationQuery converts a country and selection of regions to an api query string
-func apiLocationQuery(country Country, regions ...Region) string {
+// apiLocationQuery converts a country and selection of regions to an api query string.
+// An error is returned if the country or any of the regions are not valid, or a region is not in the country.
+func apiLocationQuery(country Country, regions ...Region) (string, error) {
 	if !Countries.Contains(country) {
-		return ""
+		return "", fmt.Errorf("country '%s' is not valid", country.Value)
+	}
+	err := validateRegions(country, regions)
+	if err != nil {
+		return "", err
 	}
 
 	queryParts := make([]string, 0, len(regions)+1)
@@ -114,13 +122,24 @@ func apiLocationQuery(country Country, regions ...Region) string {
 	queryParts = append(queryParts, countryQuery)
 
 	for _, region := range regions {
-		if Regions.Contains(region) {
-			regionQuery := fmt.Sprintf("%s=%s", regionQueryKey, region.Value)
-			queryParts = append(queryParts, regionQuery)
-		}
+		regionQuery := fmt.Sprintf("%s=%s", regionQueryKey, region.Value)
+		queryParts = append(queryParts, regionQuery)
 	}
 
-	return strings.Join(queryParts, ",")
+	return strings.Join(queryParts, ","), nil
+}
+
+// validateRegions checks regions are valid, and are regions of the country.
+func validateRegions(country Country, regions []Region) error {
+	for _, region := range regions {
+		if !Regions.Contains(region) {
+			return fmt.Errorf("region '%s' is not valid", region.Value)
+		}
+		if !country.HasRegion(region) {
+			return fmt.Errorf("region '%s' is not in country '%s'", region.Value, country.Value)
+		}
+	}
+	return nil
 }
 
 // cloneUrl clones a url. Copied directly from net/http internals
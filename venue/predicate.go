@@ -0,0 +1,106 @@
+package venue
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/filter"
+)
+
+// Is creates a predicate that matches ticket listings with a venue that is any of the specified venues.
+//
+// Venues are specified by name or alias (e.g. "The O2" or "O2 Arena"), and are resolved using the registry
+// when the predicate is created. Listing venues are resolved using the registry when matched (see
+// Registry.Resolve), so listings match regardless of how their venue name is spelt.
+// Venues (and listing venues) that are not in the registry are matched by their normalised name.
+//
+// If names is empty, any venue will match.
+func Is(registry *Registry, names ...string) filter.TicketListingPredicate {
+	return newPredicate(registry, names...).Matches
+}
+
+// ExplainIs creates an explainer for Is. See filter.Evaluate.
+func ExplainIs(registry *Registry, names ...string) filter.TicketListingExplainer {
+	return newPredicate(registry, names...).Explain
+}
+
+// newPredicate creates a predicate matching ticket listing venues that are any of the specified venues.
+func newPredicate(registry *Registry, names ...string) predicate {
+	venueKeys := make([]string, 0, len(names))
+	venueNames := make([]string, 0, len(names))
+	for _, name := range names {
+		if filter.NormaliseName(name) == "" {
+			continue
+		}
+
+		record, ok := registry.Lookup(name)
+		if ok {
+			name = record.Name
+		}
+		venueKeys = append(venueKeys, venueKey(record, ok, name))
+		venueNames = append(venueNames, name)
+	}
+
+	return predicate{
+		registry:   registry,
+		venueKeys:  venueKeys,
+		venueNames: strings.Join(venueNames, ", "),
+	}
+}
+
+// predicate is a predicate matching ticket listing venues.
+type predicate struct {
+	registry   *Registry
+	venueKeys  []string
+	venueNames string
+}
+
+func (p predicate) Matches(listing twigots.TicketListing) bool {
+	// If no venues specified, match any venue
+	if len(p.venueKeys) == 0 {
+		return true
+	}
+
+	venue := listing.Event.Venue
+	record, ok := p.registry.Resolve(venue)
+	return slices.Contains(p.venueKeys, venueKey(record, ok, venue.Name))
+}
+
+func (p predicate) Explain(listing twigots.TicketListing) filter.Verdict {
+	verdict := filter.Verdict{Predicate: "Venue"}
+
+	// If no venues specified, match any venue
+	if len(p.venueKeys) == 0 {
+		verdict.Matched = true
+		verdict.Reason = "not set, matches any listing"
+		return verdict
+	}
+
+	venue := listing.Event.Venue
+	record, ok := p.registry.Resolve(venue)
+
+	venueDescription := fmt.Sprintf("%q", venue.Name)
+	if ok && filter.NormaliseName(record.Name) != filter.NormaliseName(venue.Name) {
+		venueDescription = fmt.Sprintf("%q (%s)", venue.Name, record.Name)
+	}
+
+	if slices.Contains(p.venueKeys, venueKey(record, ok, venue.Name)) {
+		verdict.Matched = true
+		verdict.Reason = fmt.Sprintf("venue %s in [%s]", venueDescription, p.venueNames)
+		return verdict
+	}
+
+	verdict.Reason = fmt.Sprintf("venue %s not in [%s]", venueDescription, p.venueNames)
+	return verdict
+}
+
+// venueKey gets the key used to compare venues. Venues in the registry are compared by their canonical name,
+// and venues not in the registry by their own name.
+func venueKey(record Record, inRegistry bool, name string) string {
+	if inRegistry {
+		return "record:" + filter.NormaliseName(record.Name)
+	}
+	return "name:" + filter.NormaliseName(name)
+}
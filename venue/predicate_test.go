@@ -0,0 +1,37 @@
+package venue
+
+import (
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/filter"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsPredicate(t *testing.T) {
+	registry := NewDefaultRegistry()
+	listing := func(venueName string) twigots.TicketListing {
+		return twigots.TicketListing{Event: twigots.Event{Venue: twigots.Venue{Name: venueName}}}
+	}
+
+	predicate := Is(registry, "O2 Arena")
+	require.True(t, predicate(listing("The O2")))
+	require.True(t, predicate(listing("North Greenwich Arena")))
+	require.False(t, predicate(listing("O2 Academy Brixton")))
+	require.False(t, predicate(listing("The O2 Dublin")))
+
+	result := filter.Evaluate(listing("North Greenwich Arena"), ExplainIs(registry, "O2 Arena"))
+	require.Equal(t, `Venue venue "North Greenwich Arena" (The O2) in [The O2]`, result.Verdicts[0].String())
+
+	result = filter.Evaluate(listing("Wembley Arena"), ExplainIs(registry, "O2 Arena"))
+	require.Equal(t, `Venue venue "Wembley Arena" (OVO Arena Wembley) not in [The O2]`, result.Verdicts[0].String())
+
+	// Venues not in the registry should be matched by name
+	predicate = Is(registry, "Village Hall", "Wembley")
+	require.True(t, predicate(listing("The Village Hall")))
+	require.True(t, predicate(listing("Wembley Stadium")))
+	require.False(t, predicate(listing("Village Hall Annex")))
+
+	// No venues should match anything
+	require.True(t, Is(registry)(listing("Anywhere")))
+}
@@ -0,0 +1,393 @@
+// Package venue provides a registry of venues, used to resolve the venues of ticket listings
+// (whose names are spelt inconsistently) to canonical venue records.
+package venue
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/ahobsonsayers/twigots/filter"
+	"github.com/ahobsonsayers/twigots/geo"
+)
+
+//go:embed venues.json
+var defaultVenuesJSON string
+
+// Record is the canonical record of a venue.
+type Record struct {
+	// Ids are the twickets ids of the venue. A venue can have multiple ids if it has been listed more than once.
+	Ids []string
+	// Name is the canonical name of the venue.
+	Name string
+	// Aliases are the alternative names of the venue e.g. former names or common abbreviations.
+	Aliases []string
+
+	Postcode string
+	Country  twigots.Country
+	// Coordinates of the venue. Nil if unknown.
+	Coordinates *geo.Coordinates
+	// Capacity is the approximate maximum capacity of the venue. 0 if unknown.
+	Capacity int
+}
+
+// Names are the name and aliases of the venue.
+func (r Record) Names() []string {
+	return append([]string{r.Name}, r.Aliases...)
+}
+
+// clone returns a deep copy of the record, so it can be returned without exposing the registry's copy.
+func (r *Record) clone() Record {
+	record := *r
+	record.Ids = slices.Clone(r.Ids)
+	record.Aliases = slices.Clone(r.Aliases)
+	if r.Coordinates != nil {
+		coordinates := *r.Coordinates
+		record.Coordinates = &coordinates
+	}
+	return record
+}
+
+// recordJSON is the JSON format of a record.
+type recordJSON struct {
+	Ids       []string        `json:"ids"`
+	Name      string          `json:"name"`
+	Aliases   []string        `json:"aliases"`
+	Postcode  string          `json:"postcode"`
+	Country   twigots.Country `json:"country"`
+	Latitude  *float64        `json:"latitude"`
+	Longitude *float64        `json:"longitude"`
+	Capacity  int             `json:"capacity"`
+}
+
+// Registry is a registry of venues.
+//
+// Venues are resolved to records using their twickets id, or if their id is not known, their name.
+// Names are normalised (see filter.NormaliseName) before being looked up, so names that only differ in case,
+// accents, punctuation or a leading "the" resolve to the same record.
+//
+// A Registry is safe for concurrent use.
+type Registry struct {
+	mutex   sync.RWMutex
+	records []*Record
+	byId    map[string]*Record
+	byName  map[string]*Record
+}
+
+// NewRegistry creates a registry seeded with venue records.
+// Records with ids or names matching an earlier record are merged into the earlier record.
+func NewRegistry(records ...Record) *Registry {
+	registry := &Registry{
+		byId:   make(map[string]*Record),
+		byName: make(map[string]*Record),
+	}
+	for _, record := range records {
+		registry.Add(record)
+	}
+	return registry
+}
+
+// ParseRegistry parses a registry from JSON.
+// JSON should be an array of venues e.g.
+//
+//	[{"ids": ["123"], "name": "The O2", "aliases": ["O2 Arena"], "postcode": "SE10 0DX", "country": "GB",
+//	"latitude": 51.503, "longitude": 0.0032, "capacity": 20000}]
+//
+// Only name is required. If coordinates are not set, they are resolved from the postcode if possible.
+// See geo.PostcodeCoordinates.
+func ParseRegistry(r io.Reader) (*Registry, error) {
+	var venues []recordJSON
+	err := json.NewDecoder(r).Decode(&venues)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode venues: %w", err)
+	}
+
+	records := make([]Record, 0, len(venues))
+	for _, venue := range venues {
+		if strings.TrimSpace(venue.Name) == "" {
+			return nil, fmt.Errorf("venue with ids %v has no name", venue.Ids)
+		}
+		if (venue.Latitude == nil) != (venue.Longitude == nil) {
+			return nil, fmt.Errorf("venue '%s' must have both a latitude and longitude, or neither", venue.Name)
+		}
+
+		record := Record{
+			Ids:      venue.Ids,
+			Name:     venue.Name,
+			Aliases:  venue.Aliases,
+			Postcode: venue.Postcode,
+			Country:  venue.Country,
+			Capacity: venue.Capacity,
+		}
+		if venue.Latitude != nil {
+			record.Coordinates = &geo.Coordinates{Latitude: *venue.Latitude, Longitude: *venue.Longitude}
+		} else {
+			record.Coordinates = postcodeCoordinates(venue.Postcode)
+		}
+
+		records = append(records, record)
+	}
+
+	return NewRegistry(records...), nil
+}
+
+// LoadRegistry loads a registry from a JSON file. See ParseRegistry for the file format.
+func LoadRegistry(path string) (*Registry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open venues file: %w", err)
+	}
+	defer file.Close()
+
+	return ParseRegistry(file)
+}
+
+// NewDefaultRegistry creates a registry seeded with the built-in venues.
+// The built-in venues are a selection of large UK and Irish venues, with their former names as aliases.
+//
+// A new registry is created on every call, so venues observed by one registry do not affect another.
+func NewDefaultRegistry() *Registry {
+	registry, err := ParseRegistry(strings.NewReader(defaultVenuesJSON))
+	if err != nil {
+		// An error will never occur if the embedded venues are valid.
+		// If an error does occur (due to an error in the venues file), panic so we catch it.
+		panic(err)
+	}
+	return registry
+}
+
+// Len returns the number of venues in the registry.
+func (r *Registry) Len() int {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return len(r.records)
+}
+
+// Records returns all venue records in the registry, in the order they were added.
+func (r *Registry) Records() []Record {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	records := make([]Record, 0, len(r.records))
+	for _, record := range r.records {
+		records = append(records, record.clone())
+	}
+	return records
+}
+
+// Add adds a venue record to the registry.
+// If the record has an id or name that matches an existing record, it is merged into the existing record,
+// with the existing values taking precedence.
+func (r *Registry) Add(record Record) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	existingRecord := r.lookupRecord(record.Ids, record.Names())
+	if existingRecord == nil {
+		newRecord := record.clone()
+		r.records = append(r.records, &newRecord)
+		r.index(&newRecord)
+		return
+	}
+
+	mergeRecord(existingRecord, record)
+	r.index(existingRecord)
+}
+
+// Lookup gets the record of a venue by its name or one of its aliases.
+func (r *Registry) Lookup(name string) (Record, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	record := r.lookupRecord(nil, []string{name})
+	if record == nil {
+		return Record{}, false
+	}
+	return record.clone(), true
+}
+
+// Resolve gets the record of a ticket listing venue.
+// The venue is resolved using its id if known, falling back to its name. See Observe to add unknown venues.
+func (r *Registry) Resolve(venue twigots.Venue) (Record, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	record := r.lookupRecord(venueIds(venue), venueNames(venue))
+	if record == nil {
+		return Record{}, false
+	}
+	return record.clone(), true
+}
+
+// Observe resolves a ticket listing venue to its record, adding the venue to the registry if it is not known.
+//
+// If the venue id is not known, but its name is, the id is added to the record of the name, so the venue will be
+// resolved by its id from then on. If neither are known, a new record is created from the venue.
+// If the venue is known by its id, but under a different name, the name is added to the record as an alias.
+//
+// Venues with neither an id nor a name can never be resolved, so are not added, and an empty record is returned.
+func (r *Registry) Observe(venue twigots.Venue) Record {
+	if venue.Id == "" && filter.NormaliseName(venue.Name) == "" {
+		return Record{}
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	record := r.lookupRecord(venueIds(venue), venueNames(venue))
+	if record == nil {
+		record = &Record{
+			Name:        strings.TrimSpace(venue.Name),
+			Postcode:    venue.Postcode,
+			Country:     venue.Location.Country,
+			Coordinates: postcodeCoordinates(venue.Postcode),
+		}
+		r.records = append(r.records, record)
+	}
+
+	// Add the venue spelling as an alias, so it can be resolved by name from then on
+	mergeRecord(record, Record{
+		Ids:      venueIds(venue),
+		Name:     venue.Name,
+		Postcode: venue.Postcode,
+		Country:  venue.Location.Country,
+	})
+	r.index(record)
+
+	return record.clone()
+}
+
+// ObserveListings observes the venues of ticket listings. See Observe.
+func (r *Registry) ObserveListings(listings ...twigots.TicketListing) {
+	for _, listing := range listings {
+		r.Observe(listing.Event.Venue)
+	}
+}
+
+// lookupRecord gets the record matching any of the ids, or if none match, any of the names.
+// The registry must be locked by the caller.
+func (r *Registry) lookupRecord(ids, names []string) *Record {
+	for _, id := range ids {
+		record, ok := r.byId[id]
+		if ok {
+			return record
+		}
+	}
+	for _, name := range names {
+		record, ok := r.byName[filter.NormaliseName(name)]
+		if ok {
+			return record
+		}
+	}
+	return nil
+}
+
+// index indexes a record by its ids and names. Existing ids and names are not re-indexed.
+// The registry must be locked by the caller.
+func (r *Registry) index(record *Record) {
+	for _, id := range record.Ids {
+		if _, ok := r.byId[id]; !ok {
+			r.byId[id] = record
+		}
+	}
+	for _, name := range record.Names() {
+		normalisedName := filter.NormaliseName(name)
+		if normalisedName == "" {
+			continue
+		}
+		if _, ok := r.byName[normalisedName]; !ok {
+			r.byName[normalisedName] = record
+		}
+	}
+}
+
+// mergeRecord merges a record into an existing record, with the existing values taking precedence.
+func mergeRecord(existingRecord *Record, record Record) {
+	for _, id := range record.Ids {
+		if !slices.Contains(existingRecord.Ids, id) {
+			existingRecord.Ids = append(existingRecord.Ids, id)
+		}
+	}
+
+	existingNames := existingRecord.Names()
+	for _, name := range record.Names() {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		isExisting := slices.ContainsFunc(existingNames, func(existingName string) bool {
+			return filter.NormaliseName(existingName) == filter.NormaliseName(name)
+		})
+		if !isExisting {
+			existingRecord.Aliases = append(existingRecord.Aliases, name)
+			existingNames = append(existingNames, name)
+		}
+	}
+
+	if existingRecord.Postcode == "" {
+		existingRecord.Postcode = record.Postcode
+	}
+	if existingRecord.Country.Value == "" {
+		existingRecord.Country = record.Country
+	}
+	if existingRecord.Coordinates == nil && record.Coordinates != nil {
+		coordinates := *record.Coordinates
+		existingRecord.Coordinates = &coordinates
+	}
+	if existingRecord.Capacity == 0 {
+		existingRecord.Capacity = record.Capacity
+	}
+}
+
+// venueIds gets the ids of a ticket listing venue.
+func venueIds(venue twigots.Venue) []string {
+	if venue.Id == "" {
+		return nil
+	}
+	return []string{venue.Id}
+}
+
+// venueNames gets the names a ticket listing venue could be known by, in order of preference.
+// e.g. a venue named "The O2, London" in the location "London" could be known as "The O2, London" or "The O2".
+func venueNames(venue twigots.Venue) []string {
+	names := []string{venue.Name}
+
+	normalisedName := filter.NormaliseName(venue.Name)
+	for _, locationName := range []string{venue.Location.Name, venue.Location.FullName} {
+		normalisedLocationName := filter.NormaliseName(locationName)
+		if normalisedLocationName == "" {
+			continue
+		}
+
+		// Remove the location from the start or end of the name
+		nameWithoutLocation, ok := strings.CutSuffix(normalisedName, " "+normalisedLocationName)
+		if !ok {
+			nameWithoutLocation, ok = strings.CutPrefix(normalisedName, normalisedLocationName+" ")
+		}
+		if ok {
+			names = append(names, nameWithoutLocation)
+		}
+
+		// Add the location to the end of the name
+		names = append(names, venue.Name+" "+locationName)
+	}
+
+	return names
+}
+
+// postcodeCoordinates gets the coordinates of a postcode, or nil if the postcode is unknown.
+func postcodeCoordinates(postcode string) *geo.Coordinates {
+	if postcode == "" {
+		return nil
+	}
+	coordinates, err := geo.PostcodeCoordinates(postcode)
+	if err != nil {
+		return nil
+	}
+	return &coordinates
+}
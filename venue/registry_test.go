@@ -0,0 +1,128 @@
+package venue
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ahobsonsayers/twigots"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultRegistry(t *testing.T) {
+	registry := NewDefaultRegistry()
+	require.Equal(t, 23, registry.Len())
+
+	// Every venue should have coordinates, and only be found by its own names
+	for _, record := range registry.Records() {
+		require.NotNil(t, record.Coordinates, record.Name)
+		for _, name := range record.Names() {
+			foundRecord, ok := registry.Lookup(name)
+			require.True(t, ok, name)
+			require.Equal(t, record.Name, foundRecord.Name, name)
+		}
+	}
+
+	record, ok := registry.Lookup("o2 arena london")
+	require.True(t, ok)
+	require.Equal(t, "The O2", record.Name)
+	require.Equal(t, 20000, record.Capacity)
+	require.Equal(t, twigots.CountryUnitedKingdom, record.Country)
+
+	_, ok = registry.Lookup("Not A Venue")
+	require.False(t, ok)
+}
+
+func TestRegistryResolve(t *testing.T) {
+	registry := NewDefaultRegistry()
+
+	// By id
+	record, ok := registry.Resolve(twigots.Venue{Id: "771714953575927808", Name: "Stadium"})
+	require.True(t, ok)
+	require.Equal(t, "London Stadium", record.Name)
+
+	// By name, with different spellings
+	venues := []twigots.Venue{
+		{Name: "The O2"},
+		{Name: "O2 Arena", Location: twigots.Location{Name: "London"}},
+		{Name: "The O2, London", Location: twigots.Location{Name: "London"}},
+		{Name: "London - The O2", Location: twigots.Location{Name: "London"}},
+		{Name: "the o2 arena"},
+	}
+	for _, venue := range venues {
+		record, ok := registry.Resolve(venue)
+		require.True(t, ok, venue.Name)
+		require.Equal(t, "The O2", record.Name, venue.Name)
+	}
+
+	_, ok = registry.Resolve(twigots.Venue{Id: "1", Name: "Village Hall"})
+	require.False(t, ok)
+}
+
+func TestRegistryObserve(t *testing.T) {
+	registry := NewDefaultRegistry()
+	numVenues := registry.Len()
+
+	// Known names should link ids to existing records
+	record := registry.Observe(twigots.Venue{Id: "123", Name: "Hammersmith Apollo"})
+	require.Equal(t, "Eventim Apollo", record.Name)
+	require.Contains(t, record.Ids, "123")
+	require.Equal(t, numVenues, registry.Len())
+
+	// The id should then resolve, whatever the name
+	record, ok := registry.Resolve(twigots.Venue{Id: "123", Name: "Apollo Hammersmith"})
+	require.True(t, ok)
+	require.Equal(t, "Eventim Apollo", record.Name)
+
+	// Unknown venues should be added
+	venue := twigots.Venue{
+		Id:       "456",
+		Name:     "Village Hall",
+		Postcode: "M1 1AE",
+		Location: twigots.Location{Country: twigots.CountryUnitedKingdom},
+	}
+	record = registry.Observe(venue)
+	require.Equal(t, "Village Hall", record.Name)
+	require.Equal(t, []string{"456"}, record.Ids)
+	require.NotNil(t, record.Coordinates)
+	require.Equal(t, numVenues+1, registry.Len())
+
+	// New spellings of known ids should be added as aliases
+	record = registry.Observe(twigots.Venue{Id: "456", Name: "The Village Hall (Main Room)"})
+	require.Equal(t, "Village Hall", record.Name)
+	require.Equal(t, []string{"The Village Hall (Main Room)"}, record.Aliases)
+	require.Equal(t, numVenues+1, registry.Len())
+
+	// Returned records should not be shared with the registry
+	record.Aliases[0] = "Changed"
+	record, _ = registry.Lookup("village hall main room")
+	require.Equal(t, []string{"The Village Hall (Main Room)"}, record.Aliases)
+
+	// Venues with neither an id nor a name should not be added
+	for range 2 {
+		record = registry.Observe(twigots.Venue{Name: " !? "})
+		require.Equal(t, Record{}, record)
+	}
+	require.Equal(t, numVenues+1, registry.Len())
+}
+
+func TestParseRegistry(t *testing.T) {
+	registry, err := ParseRegistry(strings.NewReader(`[
+		{"name": "Venue", "aliases": ["Other Venue"], "postcode": "LS2 8BY"},
+		{"ids": ["1"], "name": "Other Venue", "capacity": 100}
+	]`))
+	require.NoError(t, err)
+
+	// Venues sharing names should be merged
+	require.Equal(t, 1, registry.Len())
+	record, ok := registry.Lookup("Venue")
+	require.True(t, ok)
+	require.Equal(t, []string{"1"}, record.Ids)
+	require.Equal(t, 100, record.Capacity)
+	require.NotNil(t, record.Coordinates) // From postcode
+
+	_, err = ParseRegistry(strings.NewReader(`[{"aliases": ["Venue"]}]`))
+	require.Error(t, err)
+
+	_, err = ParseRegistry(strings.NewReader(`[{"name": "Venue", "latitude": 51}]`))
+	require.Error(t, err)
+}